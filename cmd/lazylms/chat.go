@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+)
+
+// Exit codes of the chat subcommand
+const (
+	exitChatFailed      = 1
+	exitSchemaViolation = 2
+)
+
+// chatCommand sends a single prompt without starting the TUI, so lazylms can
+// be used from scripts and pipes: git diff | lazylms chat "review this"
+func chatCommand(config *client.ClientConfig) *cli.Command {
+	return &cli.Command{
+		Name:      "chat",
+		Usage:     "Send a prompt and stream the answer to stdout",
+		ArgsUsage: "[prompt]",
+		Description: "The prompt is taken from the arguments, piped input is appended to it. " +
+			"Reasoning is written to stderr.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "model",
+				Aliases: []string{"m"},
+				Usage:   "Loaded model identifier, defaults to default_model or the first loaded model",
+			},
+			&cli.StringFlag{
+				Name:    "system",
+				Aliases: []string{"s"},
+				Usage:   "System prompt, defaults to system_prompt from the config file",
+			},
+			&cli.StringFlag{
+				Name:  "preset",
+				Usage: "Named sampling preset, individual sampling flags override it",
+			},
+			&cli.Float64Flag{
+				Name:        "temperature",
+				Usage:       "Sampling temperature",
+				DefaultText: "server default",
+			},
+			&cli.Float64Flag{
+				Name:        "top-p",
+				Usage:       "Nucleus sampling probability",
+				DefaultText: "server default",
+			},
+			&cli.IntFlag{
+				Name:        "max-tokens",
+				Usage:       "Maximum number of output tokens",
+				DefaultText: "server default",
+			},
+			&cli.IntFlag{
+				Name:        "seed",
+				Usage:       "Sampling seed",
+				DefaultText: "random",
+			},
+			&cli.StringSliceFlag{
+				Name:  "stop",
+				Usage: "Stop sequence, can be repeated",
+			},
+			&cli.StringFlag{
+				Name:  "schema",
+				Usage: "JSON Schema file the answer must follow, violations exit with status 2",
+			},
+			&cli.BoolFlag{
+				Name:  "hide-reasoning",
+				Usage: "Do not write reasoning to stderr",
+			},
+			verboseFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			return runChat(c, *config)
+		},
+	}
+}
+
+func runChat(c *cli.Context, config client.ClientConfig) error {
+	prompt, err := readPrompt(c.Args().Slice(), os.Stdin)
+	if err != nil {
+		return cli.Exit(err.Error(), exitChatFailed)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	lmsClient, err := newHeadlessClient(ctx, config, c.Bool("verbose"))
+	if err != nil {
+		return cli.Exit(err.Error(), exitChatFailed)
+	}
+	defer lmsClient.Cleanup()
+
+	if err := configureChatClient(c, lmsClient, config); err != nil {
+		return cli.Exit(err.Error(), exitChatFailed)
+	}
+
+	model := config.DefaultModel
+	if c.IsSet("model") {
+		model = c.String("model")
+	}
+
+	out := &chatWriter{hideReasoning: c.Bool("hide-reasoning") || config.UI.HideReasoning}
+	err = lmsClient.SendMessageStreamWithModel(ctx, prompt, model, out.write)
+	out.finish()
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("chat failed: %v", err), exitChatFailed)
+	}
+
+	if validation := lmsClient.LastValidation(); validation != nil && !validation.Valid() {
+		return cli.Exit(fmt.Sprintf("output violates schema %s:\n  %s",
+			validation.Schema, strings.Join(validation.Violations, "\n  ")), exitSchemaViolation)
+	}
+	return nil
+}
+
+// readPrompt joins the prompt arguments with piped input
+func readPrompt(args []string, stdin *os.File) (string, error) {
+	parts := make([]string, 0, 2)
+	if prompt := strings.TrimSpace(strings.Join(args, " ")); prompt != "" {
+		parts = append(parts, prompt)
+	}
+
+	if info, err := stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice == 0 {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		if piped := strings.TrimSpace(string(data)); piped != "" {
+			parts = append(parts, piped)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", fmt.Errorf("no prompt given, pass it as an argument or pipe it to stdin")
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// configureChatClient applies the system prompt, sampling, schema and tool
+// settings to the client
+func configureChatClient(c *cli.Context, lmsClient *client.Client, config client.ClientConfig) error {
+	system := config.SystemPrompt
+	if c.IsSet("system") {
+		system = c.String("system")
+	}
+	if system != "" {
+		if err := lmsClient.SetSystemMessage(system); err != nil {
+			return err
+		}
+	}
+
+	params, err := samplingFromFlags(c)
+	if err != nil {
+		return err
+	}
+	if err := lmsClient.SetSamplingParams(params); err != nil {
+		return err
+	}
+
+	if path := c.String("schema"); path != "" {
+		schema, err := client.LoadJSONSchema(path)
+		if err != nil {
+			return err
+		}
+		lmsClient.SetSchema(schema)
+	}
+
+	if config.Tools {
+		if err := lmsClient.RegisterBuiltinTools(); err != nil {
+			return fmt.Errorf("failed to register tools: %w", err)
+		}
+	}
+	return nil
+}
+
+// samplingFromFlags starts from the named preset and overrides it with the
+// sampling flags that were given
+func samplingFromFlags(c *cli.Context) (client.SamplingParams, error) {
+	var params client.SamplingParams
+
+	if name := c.String("preset"); name != "" {
+		prefs, err := client.LoadPreferences()
+		if err != nil {
+			return params, err
+		}
+		preset, ok := prefs.Preset(name)
+		if !ok {
+			return params, fmt.Errorf("unknown preset %q, available: %s", name, strings.Join(prefs.PresetNames(), ", "))
+		}
+		params = preset
+	}
+
+	if c.IsSet("temperature") {
+		temperature := c.Float64("temperature")
+		params.Temperature = &temperature
+	}
+	if c.IsSet("top-p") {
+		topP := c.Float64("top-p")
+		params.TopP = &topP
+	}
+	if c.IsSet("max-tokens") {
+		maxTokens := c.Int("max-tokens")
+		params.MaxOutputTokens = &maxTokens
+	}
+	if c.IsSet("seed") {
+		seed := c.Int("seed")
+		params.Seed = &seed
+	}
+	if c.IsSet("stop") {
+		params.Stop = c.StringSlice("stop")
+	}
+	return params, nil
+}
+
+// chatWriter streams answer text to stdout and everything else to stderr
+type chatWriter struct {
+	hideReasoning bool
+	// lastStdout is the last byte written to stdout, used to end the
+	// answer with a newline
+	lastStdout byte
+	// lastStream is the stream of the previous chunk, a newline separates
+	// reasoning on stderr from the answer that follows it
+	lastStream *os.File
+}
+
+func (w *chatWriter) write(text, contentType string) {
+	switch contentType {
+	case client.ContentTypeOutput:
+		w.emit(os.Stdout, text)
+	case client.ContentTypeReasoning:
+		if !w.hideReasoning {
+			w.emit(os.Stderr, text)
+		}
+	case client.ContentTypeTool, client.ContentTypeSources:
+		w.emit(os.Stderr, text)
+	}
+}
+
+func (w *chatWriter) emit(stream *os.File, text string) {
+	if text == "" {
+		return
+	}
+	if w.lastStream == os.Stderr && stream == os.Stdout {
+		fmt.Fprintln(os.Stderr)
+	}
+	fmt.Fprint(stream, text)
+	w.lastStream = stream
+	if stream == os.Stdout {
+		w.lastStdout = text[len(text)-1]
+	}
+}
+
+// finish terminates the answer with a newline
+func (w *chatWriter) finish() {
+	if w.lastStdout != 0 && w.lastStdout != '\n' {
+		fmt.Fprintln(os.Stdout)
+	}
+}
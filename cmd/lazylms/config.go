@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+)
+
+// loadConfig returns the default configuration with the config file and
+// profile applied, along with the config file. Without a config file the
+// defaults and a nil file are returned, unless a profile was asked for. The
+// configuration is validated by the caller, after applyFlags.
+func loadConfig(path, profile string) (client.ClientConfig, *client.ConfigFile, error) {
+	config := client.DefaultClientConfig()
+
+	if path == "" {
+		defaultPath, err := client.DefaultConfigFilePath()
+		if err != nil {
+			return config, nil, err
+		}
+		path = defaultPath
+	}
+	if path == "" {
+		if profile != "" {
+			return config, nil, fmt.Errorf("--profile %s needs a config file, none was found", profile)
+		}
+		return config, nil, nil
+	}
+
+	file, err := client.LoadConfigFile(path)
+	if err != nil {
+		return config, nil, err
+	}
+	if err := file.Apply(&config, profile); err != nil {
+		return config, nil, err
+	}
+	return config, file, nil
+}
+
+// applyFlags overrides the configuration with the flags given on the
+// command line
+func applyFlags(c *cli.Context, config *client.ClientConfig) error {
+	if c.IsSet("host") {
+		config.Host = c.String("host")
+	}
+	if c.IsSet("port") {
+		config.Port = c.String("port")
+	}
+	if c.IsSet("scheme") {
+		config.Scheme = c.String("scheme")
+	}
+	if c.IsSet("timeout") {
+		config.HTTPTimeout = c.Duration("timeout")
+	}
+	if c.IsSet("max-retries") {
+		config.MaxRetries = c.Int("max-retries")
+	}
+	if c.IsSet("model-api") {
+		config.ModelAPI = c.String("model-api")
+	}
+	if c.IsSet("chat-api") {
+		config.ChatAPI = c.String("chat-api")
+	}
+	if c.IsSet("api-key-env") {
+		config.APIKeyEnv = c.String("api-key-env")
+	}
+	if c.IsSet("tls-ca") {
+		config.TLS.CAFile = c.String("tls-ca")
+	}
+	if c.IsSet("tls-cert") {
+		config.TLS.CertFile = c.String("tls-cert")
+	}
+	if c.IsSet("tls-key") {
+		config.TLS.KeyFile = c.String("tls-key")
+	}
+	if c.IsSet("tls-server-name") {
+		config.TLS.ServerName = c.String("tls-server-name")
+	}
+	if c.IsSet("tls-insecure") {
+		config.TLS.InsecureSkipVerify = c.Bool("tls-insecure")
+	}
+	for _, header := range c.StringSlice("header") {
+		name, value, err := client.ParseHeader(header)
+		if err != nil {
+			return fmt.Errorf("invalid --header: %w", err)
+		}
+		*config = config.WithHeader(name, value)
+	}
+	if c.IsSet("reasoning-effort") {
+		config.ReasoningEffort = c.String("reasoning-effort")
+	}
+	if c.IsSet("compact") {
+		config.Compact = c.Bool("compact")
+	}
+	if c.IsSet("stateful") {
+		config.Stateful = c.Bool("stateful")
+	}
+	if c.IsSet("embedding-model") {
+		config.EmbeddingModel = c.String("embedding-model")
+	}
+	if c.IsSet("rag") {
+		config.Retrieval = c.Bool("rag")
+	}
+	if c.IsSet("tools") {
+		config.Tools = c.Bool("tools")
+	}
+	return nil
+}
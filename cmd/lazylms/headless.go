@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+)
+
+// verboseFlag makes a subcommand write the client logs to stderr
+func verboseFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:    "verbose",
+		Aliases: []string{"v"},
+		Usage:   "Write client logs to stderr",
+	}
+}
+
+// newHeadlessClient creates a client for subcommands that run without the
+// TUI. The logs panel is replaced by stderr when verbose is set.
+func newHeadlessClient(ctx context.Context, config client.ClientConfig, verbose bool) (*client.Client, error) {
+	if err := client.ValidateClientConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Warnings are not logged without --verbose, this one must not go unseen
+	if config.TLS.InsecureSkipVerify {
+		fmt.Fprintf(os.Stderr, "warning: TLS certificate verification is disabled for %s\n", config.GetFullURL())
+	}
+
+	logChannel := make(chan string, config.LogChannelSize)
+	go forwardLogs(logChannel, verbose)
+
+	lmsClient, err := client.NewClientWithConfig(ctx, config, logChannel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LM Studio client: %w", err)
+	}
+	return lmsClient, nil
+}
+
+// forwardLogs drains the client log channel, writing to stderr when verbose
+func forwardLogs(logChannel chan string, verbose bool) {
+	for line := range logChannel {
+		if verbose {
+			fmt.Fprintln(os.Stderr, line)
+		}
+	}
+}
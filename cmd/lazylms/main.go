@@ -25,47 +25,137 @@ func main() {
 	sugar := logger.Sugar()
 
 	config := client.DefaultClientConfig()
+	// Profiles of the config file, offered by the server switcher
+	var servers []client.ServerProfile
 
 	app := &cli.App{
 		Name:  "lazylms",
 		Usage: "TUI client for LM Studio",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:        "host",
-				Value:       config.Host,
-				Usage:       "LM Studio host address",
-				Destination: &config.Host,
+				Name:  "config",
+				Usage: "Config file, defaults to $" + client.ConfigPathEnv + " or config.toml in the lazylms config directory",
 			},
 			&cli.StringFlag{
-				Name:        "port",
-				Value:       config.Port,
-				Usage:       "LM Studio port",
-				Destination: &config.Port,
+				Name:  "profile",
+				Usage: "Config file profile to use instead of default_profile",
 			},
 			&cli.StringFlag{
-				Name:        "scheme",
-				Value:       config.Scheme,
-				Usage:       "Scheme (http or https)",
-				Destination: &config.Scheme,
+				Name:  "host",
+				Value: config.Host,
+				Usage: "LM Studio host address",
+			},
+			&cli.StringFlag{
+				Name:  "port",
+				Value: config.Port,
+				Usage: "LM Studio port",
+			},
+			&cli.StringFlag{
+				Name:  "scheme",
+				Value: config.Scheme,
+				Usage: "Scheme (http or https)",
 			},
 			&cli.DurationFlag{
-				Name:        "timeout",
-				Value:       config.HTTPTimeout,
-				Usage:       "HTTP request timeout",
-				Destination: &config.HTTPTimeout,
+				Name:  "timeout",
+				Value: config.HTTPTimeout,
+				Usage: "HTTP request timeout",
 			},
 			&cli.IntFlag{
-				Name:        "max-retries",
-				Value:       config.MaxRetries,
-				Usage:       "Maximum number of HTTP retries",
-				Destination: &config.MaxRetries,
+				Name:  "max-retries",
+				Value: config.MaxRetries,
+				Usage: "Maximum number of HTTP retries",
+			},
+			&cli.StringFlag{
+				Name:  "model-api",
+				Value: config.ModelAPI,
+				Usage: "API used to list and manage models (rest or cli)",
+			},
+			&cli.StringFlag{
+				Name:  "chat-api",
+				Value: config.ChatAPI,
+				Usage: "API used for chat (auto, responses or completions)",
+			},
+			&cli.StringFlag{
+				Name:  "api-key-env",
+				Value: config.APIKeyEnv,
+				Usage: "Environment variable holding the API key sent as a bearer token",
+			},
+			&cli.StringFlag{
+				Name:  "tls-ca",
+				Usage: "PEM bundle of certificate authorities trusted for https servers",
+			},
+			&cli.StringFlag{
+				Name:  "tls-cert",
+				Usage: "Client certificate for mutual TLS",
+			},
+			&cli.StringFlag{
+				Name:  "tls-key",
+				Usage: "Key of the client certificate",
+			},
+			&cli.StringFlag{
+				Name:  "tls-server-name",
+				Usage: "Name expected in the server certificate, when it differs from the host",
+			},
+			&cli.BoolFlag{
+				Name:  "tls-insecure",
+				Usage: "Skip verification of the server certificate (unsafe)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "header",
+				Usage: "Extra request header as \"Name: value\", can be repeated",
+			},
+			&cli.StringFlag{
+				Name:  "reasoning-effort",
+				Value: config.ReasoningEffort,
+				Usage: "Reasoning effort for thinking models (off, low, medium or high)",
+			},
+			&cli.BoolFlag{
+				Name:  "compact",
+				Usage: "Summarize old turns with the model instead of dropping them when the context fills up",
+			},
+			&cli.BoolFlag{
+				Name:  "stateful",
+				Usage: "Store responses on the server and send only the new turn with previous_response_id",
+			},
+			&cli.StringFlag{
+				Name:  "embedding-model",
+				Value: config.EmbeddingModel,
+				Usage: "Model used to embed files for /index and retrieval",
+			},
+			&cli.BoolFlag{
+				Name:  "rag",
+				Usage: "Add relevant chunks from the /index vector index to prompts",
+			},
+			&cli.BoolFlag{
+				Name:  "tools",
+				Usage: "Let models call the built-in tools",
 			},
 		},
-		Action: func(c *cli.Context) error {
-			if err := client.ValidateClientConfig(config); err != nil {
+		Before: func(c *cli.Context) error {
+			loaded, file, err := loadConfig(c.String("config"), c.String("profile"))
+			if err != nil {
+				return err
+			}
+			if err := applyFlags(c, &loaded); err != nil {
+				return err
+			}
+			// Validated once flags had the chance to complete the profile
+			if err := client.ValidateClientConfig(loaded); err != nil {
 				return fmt.Errorf("invalid configuration: %w", err)
 			}
-			return runApp(sugar, config)
+			config = loaded
+			if file != nil {
+				servers = file.Servers()
+			}
+			return nil
+		},
+		Commands: []*cli.Command{
+			chatCommand(&config),
+			modelsCommand(&config),
+			serveCommand(&config),
+		},
+		Action: func(c *cli.Context) error {
+			return runApp(sugar, config, servers)
 		},
 	}
 
@@ -74,7 +164,7 @@ func main() {
 	}
 }
 
-func runApp(sugar *zap.SugaredLogger, config client.ClientConfig) error {
+func runApp(sugar *zap.SugaredLogger, config client.ClientConfig, servers []client.ServerProfile) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -85,6 +175,12 @@ func runApp(sugar *zap.SugaredLogger, config client.ClientConfig) error {
 		return fmt.Errorf("failed to create LM Studio client: %w", err)
 	}
 
+	if config.Tools {
+		if err := lmsClient.RegisterBuiltinTools(); err != nil {
+			return fmt.Errorf("failed to register tools: %w", err)
+		}
+	}
+
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
 
@@ -106,7 +202,7 @@ func runApp(sugar *zap.SugaredLogger, config client.ClientConfig) error {
 		os.Exit(0)
 	}()
 
-	model := tui.NewModel(lmsClient, logChannel)
+	model := tui.NewModel(lmsClient, logChannel, servers)
 
 	defer model.Cleanup()
 
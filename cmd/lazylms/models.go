@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+)
+
+// Exit statuses of models estimate besides success and errors
+const (
+	exitModelUnavailable    = 2 // The model would not fit
+	exitEstimateUnsupported = 3 // The server cannot estimate, nothing was checked
+)
+
+// modelAction is the result of downloading, loading or unloading a model
+type modelAction struct {
+	Model  string `json:"model"`
+	Action string `json:"action"` // "downloaded", "loaded" or "unloaded"
+}
+
+// modelEstimate is the result of a load estimate
+type modelEstimate struct {
+	Model       string `json:"model"`
+	Estimate    string `json:"estimate"`                     // "available", "unavailable" or "unsupported"
+	GPUMemory   int64  `json:"gpu_memory_bytes,omitempty"`   // Estimated GPU memory
+	TotalMemory int64  `json:"total_memory_bytes,omitempty"` // Estimated total memory
+	Reason      string `json:"reason,omitempty"`
+}
+
+// modelsCommand manages models without the TUI, for CI jobs and Makefiles
+func modelsCommand(config *client.ClientConfig) *cli.Command {
+	return &cli.Command{
+		Name:  "models",
+		Usage: "List, download, load and unload models",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "ls",
+				Usage: "List downloaded models",
+				Flags: []cli.Flag{outputFlag(), verboseFlag()},
+				Action: func(c *cli.Context) error {
+					return withModelsClient(c, *config, listDownloadedModels)
+				},
+			},
+			{
+				Name:  "ps",
+				Usage: "List loaded models",
+				Flags: []cli.Flag{outputFlag(), verboseFlag()},
+				Action: func(c *cli.Context) error {
+					return withModelsClient(c, *config, listLoadedModels)
+				},
+			},
+			{
+				Name:      "get",
+				Usage:     "Download a model by key or search term, reporting progress on stderr",
+				ArgsUsage: "<model key or search term>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "yes",
+						Aliases: []string{"y"},
+						Usage:   "Download the best match of a search term without asking",
+					},
+					outputFlag(),
+					verboseFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					return withModelsClient(c, *config, downloadModel)
+				},
+			},
+			{
+				Name:      "load",
+				Usage:     "Load a model",
+				ArgsUsage: "<model key>",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:        "context-length",
+						Usage:       "Context length in tokens, up to the model maximum",
+						DefaultText: "model default",
+					},
+					&cli.StringFlag{
+						Name:        "gpu",
+						Usage:       "GPU offload: max, off or a ratio between 0 and 1",
+						DefaultText: "server default",
+					},
+					&cli.DurationFlag{
+						Name:        "ttl",
+						Usage:       "Unload the model after it has been idle this long",
+						DefaultText: "never",
+					},
+					&cli.StringFlag{
+						Name:        "identifier",
+						Usage:       "Identifier to load the model under",
+						DefaultText: "model key",
+					},
+					&cli.IntFlag{
+						Name:        "parallel",
+						Usage:       "Requests the model serves at once",
+						DefaultText: "server default",
+					},
+					outputFlag(),
+					verboseFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					return withModelsClient(c, *config, loadModel)
+				},
+			},
+			{
+				Name:      "unload",
+				Usage:     "Unload a model, or all models with --all",
+				ArgsUsage: "<identifier>",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Unload every loaded model",
+					},
+					outputFlag(),
+					verboseFlag(),
+				},
+				Action: func(c *cli.Context) error {
+					return withModelsClient(c, *config, unloadModel)
+				},
+			},
+			{
+				Name:      "estimate",
+				Usage:     "Estimate whether a model fits in memory, exits with status 2 when it does not",
+				ArgsUsage: "<model key>",
+				Flags:     []cli.Flag{outputFlag(), verboseFlag()},
+				Action: func(c *cli.Context) error {
+					return withModelsClient(c, *config, estimateModel)
+				},
+			},
+		},
+	}
+}
+
+// withModelsClient checks the output format, creates a client and runs a
+// models subcommand with it. Errors exit with status 1.
+func withModelsClient(c *cli.Context, config client.ClientConfig, run func(*cli.Context, *client.Client) error) error {
+	if err := validateOutputFormat(c.String("output")); err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	lmsClient, err := newHeadlessClient(ctx, config, c.Bool("verbose"))
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+	defer lmsClient.Cleanup()
+
+	if err := run(c, lmsClient); err != nil {
+		if _, ok := err.(cli.ExitCoder); ok {
+			return err
+		}
+		return cli.Exit(err.Error(), 1)
+	}
+	return nil
+}
+
+// requireArg returns the single positional argument of a subcommand
+func requireArg(c *cli.Context, name string) (string, error) {
+	if c.NArg() != 1 {
+		return "", fmt.Errorf("expected a single %s argument, usage: %s %s", name, c.Command.HelpName, c.Command.ArgsUsage)
+	}
+	return c.Args().First(), nil
+}
+
+func listDownloadedModels(c *cli.Context, lmsClient *client.Client) error {
+	models, err := lmsClient.GetDownloadedModelsWithoutEstimates()
+	if err != nil {
+		return err
+	}
+	if models == nil {
+		models = []client.LMSDownloadedListItem{}
+	}
+
+	return writeOutput(c.App.Writer, c.String("output"), models, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "MODEL\tARCH\tQUANT\tFORMAT\tSIZE\tMAX CONTEXT")
+		for _, model := range models {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				model.ModelKey,
+				valueOrDash(model.Architecture),
+				valueOrDash(model.Quantization.Name),
+				valueOrDash(model.Format),
+				client.FormatBytes(model.SizeBytes),
+				formatContext(model.MaxContextLength))
+		}
+	})
+}
+
+func listLoadedModels(c *cli.Context, lmsClient *client.Client) error {
+	models, err := lmsClient.GetLoadedModels()
+	if err != nil {
+		return err
+	}
+	if models == nil {
+		models = []client.LMSLoadedListItem{}
+	}
+
+	return writeOutput(c.App.Writer, c.String("output"), models, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "IDENTIFIER\tMODEL\tSTATUS\tSIZE\tCONTEXT\tTTL")
+		for _, model := range models {
+			ttl := "-"
+			if model.TtlMs != nil {
+				ttl = fmt.Sprintf("%ds", *model.TtlMs/1000)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				model.Identifier,
+				model.ModelKey,
+				valueOrDash(model.Status),
+				client.FormatBytes(model.SizeBytes),
+				formatContext(model.ContextLength),
+				ttl)
+		}
+	})
+}
+
+func downloadModel(c *cli.Context, lmsClient *client.Client) error {
+	term, err := requireArg(c, "model key or search term")
+	if err != nil {
+		return err
+	}
+	model, err := client.ResolveDownload(lmsClient.Context(), term)
+	if err != nil {
+		return err
+	}
+	if model != term {
+		if !c.Bool("yes") {
+			return fmt.Errorf("%q matches %s, download it by that key or pass --yes", term, model)
+		}
+		fmt.Fprintf(c.App.ErrWriter, "%s matches %s\n", term, model)
+	}
+	job, err := lmsClient.StartDownload(lmsClient.Context(), model)
+	if err != nil {
+		return err
+	}
+
+	// Redraw one progress line, the TUI shows the same in the panel border
+	reported := -1
+	for progress := range job.Progress() {
+		if percent := int(progress.Percent); percent > reported {
+			reported = percent
+			fmt.Fprintf(c.App.ErrWriter, "\rDownloading %s %3d%%", model, percent)
+		}
+	}
+	if reported >= 0 {
+		fmt.Fprintln(c.App.ErrWriter)
+	}
+
+	if err := job.Err(); err != nil {
+		return err
+	}
+	return writeAction(c, modelAction{Model: model, Action: "downloaded"})
+}
+
+func loadModel(c *cli.Context, lmsClient *client.Client) error {
+	modelKey, err := requireArg(c, "model key")
+	if err != nil {
+		return err
+	}
+	options := client.LoadOptions{
+		ContextLength: c.Int("context-length"),
+		GPU:           c.String("gpu"),
+		TTL:           int(c.Duration("ttl").Seconds()),
+		Identifier:    c.String("identifier"),
+		Parallel:      c.Int("parallel"),
+	}
+	if c.IsSet("ttl") && options.TTL < 1 {
+		return fmt.Errorf("--ttl must be at least one second")
+	}
+
+	// Bound the context length by the model maximum when the model is known
+	maxContextLength := 0
+	if options.ContextLength > 0 {
+		if downloaded, err := lmsClient.GetDownloadedModelsWithoutEstimates(); err == nil {
+			for _, model := range downloaded {
+				if model.ModelKey == modelKey {
+					maxContextLength = model.MaxContextLength
+				}
+			}
+		}
+	}
+	if err := client.ValidateLoadOptions(options, maxContextLength); err != nil {
+		return err
+	}
+
+	if err := lmsClient.LoadModel(modelKey, options); err != nil {
+		return err
+	}
+	return writeAction(c, modelAction{Model: modelKey, Action: "loaded"})
+}
+
+func unloadModel(c *cli.Context, lmsClient *client.Client) error {
+	if c.Bool("all") {
+		if c.NArg() > 0 {
+			return fmt.Errorf("--all does not take an identifier")
+		}
+		if err := lmsClient.UnloadAllModels(); err != nil {
+			return err
+		}
+		return writeAction(c, modelAction{Model: "all", Action: "unloaded"})
+	}
+
+	identifier, err := requireArg(c, "identifier")
+	if err != nil {
+		return err
+	}
+	if err := lmsClient.UnloadModel(identifier); err != nil {
+		return err
+	}
+	return writeAction(c, modelAction{Model: identifier, Action: "unloaded"})
+}
+
+func writeAction(c *cli.Context, action modelAction) error {
+	return writeOutput(c.App.Writer, c.String("output"), action, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "%s %s\n", action.Action, action.Model)
+	})
+}
+
+func estimateModel(c *cli.Context, lmsClient *client.Client) error {
+	modelKey, err := requireArg(c, "model key")
+	if err != nil {
+		return err
+	}
+	// Unlike the TUI, scripts must not take a missing estimate for a fit
+	result, err := lmsClient.EstimateModel(modelKey)
+	unsupported := errors.Is(err, client.ErrNotSupported)
+	if err != nil && !unsupported {
+		return err
+	}
+
+	estimate := modelEstimate{
+		Model:       modelKey,
+		Estimate:    result.Status.String(),
+		GPUMemory:   result.GPUMemory,
+		TotalMemory: result.TotalMemory,
+		Reason:      strings.TrimSpace(result.Verdict + " " + result.Reason),
+	}
+	if unsupported {
+		estimate.Estimate, estimate.Reason = "unsupported", err.Error()
+	}
+	err = writeOutput(c.App.Writer, c.String("output"), estimate, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "MODEL\tESTIMATE\tGPU MEMORY\tTOTAL MEMORY")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", estimate.Model, estimate.Estimate, client.FormatBytes(estimate.GPUMemory), client.FormatBytes(estimate.TotalMemory))
+		if estimate.Reason != "" {
+			fmt.Fprintf(w, "\n%s\n", estimate.Reason)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case unsupported:
+		return cli.Exit("", exitEstimateUnsupported)
+	case result.Status != client.StatusAvailable:
+		return cli.Exit("", exitModelUnavailable)
+	}
+	return nil
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func formatContext(tokens int) string {
+	if tokens <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", tokens)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+)
+
+// estimateBackend answers load estimates with a fixed result
+type estimateBackend struct {
+	client.Backend
+	estimate client.ModelEstimate
+	err      error
+}
+
+func (b estimateBackend) GetModelEstimate(identifier string) (client.ModelEstimate, error) {
+	return b.estimate, b.err
+}
+
+// noEstimateBackend cannot estimate at all
+type noEstimateBackend struct {
+	client.Backend
+}
+
+func TestEstimateModel(t *testing.T) {
+	tests := []struct {
+		name     string
+		backend  client.Backend
+		want     string
+		wantExit int
+	}{
+		{
+			name:    "fits",
+			backend: estimateBackend{estimate: client.ModelEstimate{Status: client.StatusAvailable, GPUMemory: 5}},
+			want:    "available",
+		},
+		{
+			name:     "does not fit",
+			backend:  estimateBackend{estimate: client.ModelEstimate{Status: client.StatusUnavailable}},
+			want:     "unavailable",
+			wantExit: exitModelUnavailable,
+		},
+		{
+			name:     "lms cannot reach the server",
+			backend:  estimateBackend{err: fmt.Errorf("load estimates requires lms: %w", client.ErrNotSupported)},
+			want:     "unsupported",
+			wantExit: exitEstimateUnsupported,
+		},
+		{
+			name:     "backend without estimates",
+			backend:  noEstimateBackend{},
+			want:     "unsupported",
+			wantExit: exitEstimateUnsupported,
+		},
+		{
+			name:     "estimate failed",
+			backend:  estimateBackend{err: errors.New("lms crashed")},
+			wantExit: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lmsClient, err := client.NewClientWithBackend(context.Background(), client.DefaultClientConfig(), tt.backend, nil)
+			if err != nil {
+				t.Fatalf("NewClientWithBackend() error = %v", err)
+			}
+
+			var out bytes.Buffer
+			app := &cli.App{
+				Writer:         &out,
+				ExitErrHandler: func(*cli.Context, error) {},
+				Commands: []*cli.Command{{
+					Name:  "estimate",
+					Flags: []cli.Flag{outputFlag()},
+					Action: func(c *cli.Context) error {
+						return estimateModel(c, lmsClient)
+					},
+				}},
+			}
+			err = app.Run([]string{"lazylms", "estimate", "-o", "json", "qwen/qwen3-8b"})
+
+			exit := 0
+			var exitErr cli.ExitCoder
+			if errors.As(err, &exitErr) {
+				exit = exitErr.ExitCode()
+			} else if err != nil {
+				exit = 1
+			}
+			if exit != tt.wantExit {
+				t.Errorf("exit status = %d (%v), want %d", exit, err, tt.wantExit)
+			}
+			if tt.want == "" {
+				return
+			}
+
+			var estimate modelEstimate
+			if err := json.Unmarshal(out.Bytes(), &estimate); err != nil {
+				t.Fatalf("output %q is not an estimate: %v", out.String(), err)
+			}
+			if estimate.Estimate != tt.want {
+				t.Errorf("estimate = %q, want %q", estimate.Estimate, tt.want)
+			}
+		})
+	}
+}
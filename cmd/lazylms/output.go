@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of the scripting subcommands
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// outputFlag selects how a subcommand prints its result
+func outputFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Value:   OutputTable,
+		Usage:   "Output format (table, json or yaml)",
+	}
+}
+
+// validateOutputFormat rejects unknown --output values before any work is done
+func validateOutputFormat(format string) error {
+	switch format {
+	case OutputTable, OutputJSON, OutputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, expected table, json or yaml", format)
+	}
+}
+
+// writeOutput prints value as JSON or YAML, or calls table to print it as
+// aligned columns
+func writeOutput(w io.Writer, format string, value any, table func(*tabwriter.Writer)) error {
+	switch format {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case OutputYAML:
+		return writeYAML(w, value)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		table(tw)
+		return tw.Flush()
+	}
+}
+
+// writeYAML prints value as YAML. The value goes through its JSON encoding
+// first so field names and omitempty match the JSON output; object keys
+// come out sorted.
+func writeYAML(w io.Writer, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(yamlNumbers(generic)); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	return encoder.Close()
+}
+
+// yamlNumbers turns the JSON numbers of a decoded value into Go numbers,
+// which the YAML encoder prints unquoted
+func yamlNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if integer, err := v.Int64(); err == nil {
+			return integer
+		}
+		float, _ := v.Float64()
+		return float
+	case map[string]any:
+		for key, child := range v {
+			v[key] = yamlNumbers(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = yamlNumbers(child)
+		}
+	}
+	return value
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteYAML(t *testing.T) {
+	type model struct {
+		Key      string   `json:"model_key"`
+		Size     int64    `json:"size_bytes"`
+		Ratio    float64  `json:"ratio"`
+		Vision   bool     `json:"vision"`
+		Tags     []string `json:"tags"`
+		Optional string   `json:"optional,omitempty"`
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{
+			name:  "object with JSON field names",
+			value: model{Key: "qwen/qwen3-8b", Size: 5120000000, Ratio: 0.5, Vision: true, Tags: []string{"a", "b"}},
+			want:  "model_key: qwen/qwen3-8b\nratio: 0.5\nsize_bytes: 5120000000\ntags:\n  - a\n  - b\nvision: true\n",
+		},
+		{
+			name:  "strings YAML would misread are quoted",
+			value: map[string]string{"bool": "yes", "number": "1.5", "null": "null", "comment": "C# # note", "colon": "a: b"},
+			want:  "bool: \"yes\"\ncolon: 'a: b'\ncomment: 'C# # note'\n\"null\": \"null\"\nnumber: \"1.5\"\n",
+		},
+		{
+			name:  "list of objects",
+			value: []model{{Key: "a"}, {Key: "b", Tags: []string{}}},
+			want:  "- model_key: a\n  ratio: 0\n  size_bytes: 0\n  tags: null\n  vision: false\n- model_key: b\n  ratio: 0\n  size_bytes: 0\n  tags: []\n  vision: false\n",
+		},
+		{name: "empty list", value: []string{}, want: "[]\n"},
+		{name: "scalar", value: "text", want: "text\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeYAML(&out, tt.value); err != nil {
+				t.Fatalf("writeYAML() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("writeYAML() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+
+			var decoded any
+			if err := yaml.Unmarshal(out.Bytes(), &decoded); err != nil {
+				t.Errorf("output does not parse as YAML: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{OutputTable, OutputJSON, OutputYAML} {
+		if err := validateOutputFormat(format); err != nil {
+			t.Errorf("validateOutputFormat(%q) error = %v", format, err)
+		}
+	}
+	if err := validateOutputFormat("xml"); err == nil {
+		t.Error("validateOutputFormat(\"xml\") error = nil, want an error")
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+	"github.com/Rugz007/lazylms/pkg/proxy"
+)
+
+const serveShutdownTimeout = 5 * time.Second
+
+// serveCommand runs an OpenAI-compatible proxy in front of LM Studio
+func serveCommand(config *client.ClientConfig) *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Serve an OpenAI-compatible API that forwards to LM Studio",
+		Description: "Tools that hardcode OpenAI model names can be pointed at local models with " +
+			"--alias gpt-4o=qwen/qwen3-8b, or --alias gpt-4o=@loaded for the first loaded model.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "listen",
+				Value: proxy.DefaultListenAddress,
+				Usage: "Address to listen on",
+			},
+			&cli.StringSliceFlag{
+				Name:  "alias",
+				Usage: "Model alias as alias=model, can be repeated",
+			},
+			&cli.StringFlag{
+				Name:  "record",
+				Usage: "Append every request and response to this JSONL file",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			return runServe(c, *config)
+		},
+	}
+}
+
+func runServe(c *cli.Context, config client.ClientConfig) error {
+	aliases, err := proxy.ParseAliases(c.StringSlice("alias"))
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The server runs in the foreground, so its request log always goes to stderr
+	lmsClient, err := newHeadlessClient(ctx, config, true)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+	defer lmsClient.Cleanup()
+
+	opts := proxy.Options{Aliases: aliases}
+	if path := c.String("record"); path != "" {
+		recorder, err := proxy.NewRecorder(path)
+		if err != nil {
+			return cli.Exit(err.Error(), 1)
+		}
+		defer recorder.Close()
+		opts.Recorder = recorder
+	}
+
+	listener, err := net.Listen("tcp", c.String("listen"))
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("failed to listen: %v", err), 1)
+	}
+
+	server := &http.Server{
+		Handler:           proxy.NewServer(lmsClient, opts).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	fmt.Fprintf(os.Stderr, "Forwarding http://%s/v1 to %s\n", listener.Addr(), config.GetAPIURL())
+	for alias, target := range aliases {
+		fmt.Fprintf(os.Stderr, "  %s -> %s\n", alias, target)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return cli.Exit(fmt.Sprintf("server failed: %v", err), 1)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return cli.Exit(fmt.Sprintf("shutdown failed: %v", err), 1)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Content part types of multi-part messages
+const (
+	ContentPartInputText  = "input_text"
+	ContentPartInputImage = "input_image"
+)
+
+// ContentPart is one part of a multi-part message
+type ContentPart struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// MessageContent is the content of an input message. A single text part is
+// sent as a plain string, anything else as a list of parts.
+type MessageContent []ContentPart
+
+// TextContent returns content made of a single text part
+func TextContent(text string) MessageContent {
+	if text == "" {
+		return nil
+	}
+	return MessageContent{{Type: ContentPartInputText, Text: text}}
+}
+
+// Text returns the text parts of the content joined together
+func (m MessageContent) Text() string {
+	var text strings.Builder
+	for _, part := range m {
+		if part.Type == ContentPartInputText {
+			text.WriteString(part.Text)
+		}
+	}
+	return text.String()
+}
+
+// HasImages reports whether the content carries any image
+func (m MessageContent) HasImages() bool {
+	return slices.ContainsFunc(m, func(part ContentPart) bool {
+		return part.Type == ContentPartInputImage
+	})
+}
+
+func (m MessageContent) MarshalJSON() ([]byte, error) {
+	if len(m) == 1 && m[0].Type == ContentPartInputText {
+		return json.Marshal(m[0].Text)
+	}
+	return json.Marshal([]ContentPart(m))
+}
+
+// ImageAttachment is an image sent along with a chat message
+type ImageAttachment struct {
+	Name     string
+	MIMEType string
+	Data     []byte
+}
+
+// DataURL encodes the image as a data URL
+func (a ImageAttachment) DataURL() string {
+	return "data:" + a.MIMEType + ";base64," + base64.StdEncoding.EncodeToString(a.Data)
+}
+
+// LoadImageAttachment reads an image file for attaching to a message
+func LoadImageAttachment(path string) (ImageAttachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ImageAttachment{}, fmt.Errorf("failed to read image: %w", err)
+	}
+	if info.IsDir() {
+		return ImageAttachment{}, fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > MaxImageAttachmentSize {
+		return ImageAttachment{}, fmt.Errorf("image %s is larger than %d MB", path, MaxImageAttachmentSize>>20)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ImageAttachment{}, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	mimeType := http.DetectContentType(data)
+	if !slices.Contains(SupportedImageTypes, mimeType) {
+		return ImageAttachment{}, fmt.Errorf("unsupported image type %s, expected one of %s", mimeType, strings.Join(SupportedImageTypes, ", "))
+	}
+
+	return ImageAttachment{
+		Name:     filepath.Base(path),
+		MIMEType: mimeType,
+		Data:     data,
+	}, nil
+}
+
+// userMessageWithImages builds a conversation message carrying images
+func userMessageWithImages(text string, images []ImageAttachment) openai.ChatCompletionMessage {
+	if len(images) == 0 {
+		return openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleUser,
+			Content: text,
+		}
+	}
+
+	parts := make([]openai.ChatMessagePart, 0, len(images)+1)
+	parts = append(parts, openai.ChatMessagePart{
+		Type: openai.ChatMessagePartTypeText,
+		Text: text,
+	})
+	for _, image := range images {
+		parts = append(parts, openai.ChatMessagePart{
+			Type:     openai.ChatMessagePartTypeImageURL,
+			ImageURL: &openai.ChatMessageImageURL{URL: image.DataURL()},
+		})
+	}
+	return openai.ChatCompletionMessage{
+		Role:         openai.ChatMessageRoleUser,
+		MultiContent: parts,
+	}
+}
+
+// inputContent converts the content of a conversation message
+func inputContent(msg openai.ChatCompletionMessage) MessageContent {
+	if len(msg.MultiContent) == 0 {
+		return TextContent(msg.Content)
+	}
+
+	content := make(MessageContent, 0, len(msg.MultiContent))
+	for _, part := range msg.MultiContent {
+		switch part.Type {
+		case openai.ChatMessagePartTypeText:
+			content = append(content, ContentPart{Type: ContentPartInputText, Text: part.Text})
+		case openai.ChatMessagePartTypeImageURL:
+			if part.ImageURL != nil {
+				content = append(content, ContentPart{Type: ContentPartInputImage, ImageURL: part.ImageURL.URL})
+			}
+		}
+	}
+	return content
+}
+
+// chatMessageParts converts multi-part content for chat completions
+func chatMessageParts(content MessageContent) []openai.ChatMessagePart {
+	parts := make([]openai.ChatMessagePart, 0, len(content))
+	for _, part := range content {
+		switch part.Type {
+		case ContentPartInputText:
+			parts = append(parts, openai.ChatMessagePart{
+				Type: openai.ChatMessagePartTypeText,
+				Text: part.Text,
+			})
+		case ContentPartInputImage:
+			parts = append(parts, openai.ChatMessagePart{
+				Type:     openai.ChatMessagePartTypeImageURL,
+				ImageURL: &openai.ChatMessageImageURL{URL: part.ImageURL},
+			})
+		}
+	}
+	return parts
+}
+
+// messageText returns the text of a conversation message, noting images
+func messageText(msg openai.ChatCompletionMessage) string {
+	if len(msg.MultiContent) == 0 {
+		return msg.Content
+	}
+
+	var text strings.Builder
+	for _, part := range msg.MultiContent {
+		switch part.Type {
+		case openai.ChatMessagePartTypeText:
+			text.WriteString(part.Text)
+		case openai.ChatMessagePartTypeImageURL:
+			text.WriteString(" [image]")
+		}
+	}
+	return text.String()
+}
+
+// countImages returns the number of images in a conversation message
+func countImages(msg openai.ChatCompletionMessage) int {
+	count := 0
+	for _, part := range msg.MultiContent {
+		if part.Type == openai.ChatMessagePartTypeImageURL {
+			count++
+		}
+	}
+	return count
+}
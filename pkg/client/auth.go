@@ -0,0 +1,107 @@
+package client
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// redactedValue replaces secrets in validation errors
+const redactedValue = "[redacted]"
+
+// ResolveAPIKey returns the configured API key, or the content of the
+// APIKeyEnv environment variable. It is empty when neither is set.
+func (c ClientConfig) ResolveAPIKey() string {
+	if c.APIKey != "" {
+		return c.APIKey
+	}
+	if c.APIKeyEnv == "" {
+		return ""
+	}
+	return strings.TrimSpace(os.Getenv(c.APIKeyEnv))
+}
+
+// authTransport adds the API key and the configured headers to every
+// request, whichever API sends it
+type authTransport struct {
+	base    http.RoundTripper
+	apiKey  string
+	headers map[string]string
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if t.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	} else {
+		// Drop the placeholder token go-openai always sends
+		req.Header.Del("Authorization")
+	}
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// ParseHeader splits a "Name: value" header
+func ParseHeader(header string) (string, string, error) {
+	name, value, ok := strings.Cut(header, ":")
+	if !ok {
+		return "", "", fmt.Errorf("header %q is not in the form Name: value", header)
+	}
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if err := ValidateHeaders(map[string]string{name: value}); err != nil {
+		return "", "", err
+	}
+	return name, value, nil
+}
+
+// withHeader returns a copy of headers with name set, leaving the original
+// map, which other configurations may share, untouched
+func withHeader(headers map[string]string, name, value string) map[string]string {
+	headers = maps.Clone(headers)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	headers[name] = value
+	return headers
+}
+
+// WithHeader returns the configuration with an extra request header
+func (c ClientConfig) WithHeader(name, value string) ClientConfig {
+	c.Headers = withHeader(c.Headers, name, value)
+	return c
+}
+
+// ValidateHeaders checks header names and values. Values often hold
+// secrets and never appear in the error.
+func ValidateHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if name == "" || strings.IndexFunc(name, func(r rune) bool { return !isTokenRune(r) }) >= 0 {
+			return ValidationError{
+				Field:   "headers",
+				Value:   name,
+				Message: "header name must be a non-empty HTTP token",
+			}
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return ValidationError{
+				Field:   "headers." + name,
+				Value:   redactedValue,
+				Message: "header value cannot contain line breaks",
+			}
+		}
+	}
+	return nil
+}
+
+// isTokenRune reports whether r may appear in an HTTP header name
+func isTokenRune(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+}
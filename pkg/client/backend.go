@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotSupported is returned when a backend cannot perform an operation
+var ErrNotSupported = errors.New("operation not supported by backend")
+
+// Backend is the server-specific half of the client. It knows how to query
+// server status, manage models and stream chat responses, while Client keeps
+// the conversation state, validation and retry policy on top of it.
+type Backend interface {
+	// Name returns a short human readable name for the backend
+	Name() string
+
+	GetStatus() (Status, error)
+	GetDownloadedModels() ([]LMSDownloadedListItem, error)
+	GetLoadedModels() ([]LMSLoadedListItem, error)
+	LoadModel(modelID string, options LoadOptions) error
+	UnloadModel(modelID string) error
+	UnloadAllModels() error
+
+	// StreamChat sends req and invokes callback for every chunk of streamed
+	// content. The second callback argument is the content type ("output" or
+	// "reasoning"). Function calls requested by the model are returned in the
+	// result rather than executed.
+	StreamChat(ctx context.Context, req ResponseRequest, callback func(string, string)) (StreamResult, error)
+}
+
+// Estimator is implemented by backends that can predict whether a
+// downloaded model fits into the available resources before loading it.
+type Estimator interface {
+	GetModelEstimate(identifier string) (ModelEstimate, error)
+}
+
+// Downloader is implemented by backends that can download new models.
+// progress is called with every update until DownloadModel returns.
+type Downloader interface {
+	DownloadModel(ctx context.Context, model string, progress func(DownloadProgress)) error
+}
+
+// ModelManager is implemented by backends that can only load, unload,
+// estimate and download models under some conditions. CanManageModels
+// returns the reason when they cannot, nil otherwise.
+type ModelManager interface {
+	CanManageModels() error
+}
+
+// ResponseStore is implemented by backends whose StreamChat honours
+// ResponseRequest.Store and PreviousResponseID, so a conversation can be
+// continued on the server by sending only the new turn.
+type ResponseStore interface {
+	StoresResponses() bool
+}
+
+// StreamResult carries what a backend learned while streaming a response
+type StreamResult struct {
+	ResponseID string
+	ToolCalls  []ToolCall
+	Metrics    StreamMetrics
+}
+
+// ToolCall is a function call requested by the model
+type ToolCall struct {
+	ItemID    string
+	CallID    string
+	Name      string
+	Arguments string
+}
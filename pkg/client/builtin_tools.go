@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RegisterBuiltinTools registers the small set of tools that ship with lazylms
+func (c *Client) RegisterBuiltinTools() error {
+	builtins := []ToolDefinition{
+		{
+			Name:        "get_current_time",
+			Description: "Returns the current local date and time in RFC 3339 format",
+			Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+				return time.Now().Format(time.RFC3339), nil
+			},
+		},
+		{
+			Name:        "list_loaded_models",
+			Description: "Lists the identifiers of the models currently loaded in LM Studio",
+			Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+				models, err := c.GetLoadedModels()
+				if err != nil {
+					return "", fmt.Errorf("failed to list loaded models: %w", err)
+				}
+				identifiers := make([]string, len(models))
+				for i, model := range models {
+					identifiers[i] = model.Identifier
+				}
+				return strings.Join(identifiers, "\n"), nil
+			},
+		},
+	}
+
+	for _, def := range builtins {
+		if err := c.RegisterTool(def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
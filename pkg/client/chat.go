@@ -33,15 +33,27 @@ func (c *Client) SetSystemMessage(content string) error {
 		Content: content,
 	}
 	c.conversation = append([]openai.ChatCompletionMessage{systemMessage}, c.conversation...)
+	// A stored conversation would keep answering with the old system message
+	c.ClearResponseHistory()
 	c.logger.Info("System message updated: %s", strings.TrimSpace(content)[:min(50, len(strings.TrimSpace(content)))]+"...")
 	return nil
 }
 
 func (c *Client) SendMessageStreamWithResponses(ctx context.Context, message string, modelID string, callback func(string, string)) error {
+	return c.SendMessageStreamWithAttachments(ctx, message, modelID, nil, callback)
+}
+
+// SendMessageStreamWithAttachments sends a message with images attached.
+// Images are only accepted by models that report vision support.
+func (c *Client) SendMessageStreamWithAttachments(ctx context.Context, message string, modelID string, images []ImageAttachment, callback func(string, string)) error {
 	if c.IsClosed() {
 		return fmt.Errorf("client is closed")
 	}
 
+	// Cancelling stops compaction and retrieval as well as the response
+	ctx, cancel := c.beginRequest(ctx)
+	defer cancel()
+
 	message = SanitizeInput(message)
 
 	if err := ValidateChatMessage(message); err != nil {
@@ -54,40 +66,123 @@ func (c *Client) SendMessageStreamWithResponses(ctx context.Context, message str
 		}
 	}
 
-	var activeModel string
-	var err error
-
-	if modelID != "" {
-		activeModel, err = c.GetActiveModelByID(modelID)
-	} else {
-		activeModel, err = c.GetActiveModel()
-	}
-
+	// An empty model ID falls back to the first loaded model
+	activeModel, err := c.GetLoadedModelByID(modelID)
 	if err != nil {
 		c.logger.Error("Failed to get active model: %v", err)
 		return fmt.Errorf("failed to get active model: %w", err)
 	}
 
-	userMessage := openai.ChatCompletionMessage{
-		Role:    openai.ChatMessageRoleUser,
-		Content: message,
+	if len(images) > 0 && !activeModel.Vision {
+		return fmt.Errorf("model %s does not accept images", activeModel.Identifier)
 	}
-	c.conversation = append(c.conversation, userMessage)
+
+	c.conversation = append(c.conversation, userMessageWithImages(message, images))
 
 	c.TruncateConversation(MaxConversationLength)
+	if contextLength := ModelContextLength(activeModel); contextLength > 0 {
+		budget := c.ContextBudget(contextLength)
+		if c.shouldCompact(budget) {
+			err := c.CompactConversation(ctx, activeModel.Identifier, callback)
+			if c.isCancelled() {
+				return fmt.Errorf("request cancelled by user")
+			}
+			if err != nil {
+				c.logger.Warn("Compaction failed, dropping old messages instead: %v", err)
+			}
+		}
+		c.TruncateConversationToTokens(budget)
+	}
+
 	messages := make([]InputMessage, len(c.conversation))
 	for i, msg := range c.conversation {
 		messages[i] = InputMessage{
 			Role:    string(msg.Role),
-			Content: msg.Content,
+			Content: inputContent(msg),
+		}
+	}
+
+	if c.RetrievalEnabled() {
+		results, err := c.retrieve(ctx, message)
+		if err != nil {
+			c.logger.Warn("Retrieval failed, sending the message without context: %v", err)
+		} else if len(results) > 0 {
+			last := len(messages) - 1
+			messages[last].Content = withRetrievedContext(messages[last].Content, results)
+			callback(formatSources(results), ContentTypeSources)
 		}
 	}
 
 	req := ResponseRequest{
-		Model: activeModel,
-		Input: messages,
-		Store: false,
+		SamplingParams: c.SamplingParams(),
+		Model:          activeModel.Identifier,
+		Input:          messages,
+		Store:          c.storesResponses(),
+	}
+
+	// Continue the stored conversation by sending only the new turn
+	if previousID := c.GetLastResponseID(); req.Store && previousID != nil {
+		req.PreviousResponseID = previousID
+		req.history = messages
+		req.Input = messages[len(messages)-1:]
+	}
+
+	if effort := c.ReasoningEffort(); effort != ReasoningEffortOff {
+		req.Reasoning = &ReasoningConfig{Effort: effort}
+	}
+
+	schema := c.Schema()
+	if schema == nil {
+		return c.streamResponse(ctx, req, callback)
+	}
+
+	req.Text = &TextConfig{Format: &TextFormat{
+		Type:   TextFormatJSONSchema,
+		Name:   schema.Name,
+		Schema: schema.Raw,
+		Strict: true,
+	}}
+
+	var output strings.Builder
+	err = c.streamResponse(ctx, req, func(text, contentType string) {
+		if contentType == ContentTypeOutput {
+			output.WriteString(text)
+		}
+		callback(text, contentType)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.validateOutput(schema, output.String())
+	return nil
+}
+
+// validateOutput checks a structured output and reports violations in the logs
+func (c *Client) validateOutput(schema *JSONSchema, output string) {
+	validation := schema.Validate(output)
+
+	c.mu.Lock()
+	c.lastValidation = &validation
+	c.mu.Unlock()
+
+	if validation.Valid() {
+		c.logger.Info("Output matches schema %s", schema.Name)
+		return
 	}
 
-	return c.SendResponseStream(ctx, req, callback)
+	c.logger.Warn("Output violates schema %s in %d place(s)", schema.Name, len(validation.Violations))
+	for _, violation := range validation.Violations {
+		c.logger.Warn("  %s", violation)
+	}
+}
+
+// storesResponses reports whether stateful mode is on and the backend can
+// chain stored responses
+func (c *Client) storesResponses() bool {
+	if !c.config.Stateful {
+		return false
+	}
+	store, ok := c.backend.(ResponseStore)
+	return ok && store.StoresResponses()
 }
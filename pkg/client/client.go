@@ -3,78 +3,106 @@ package client
 import (
 	"context"
 	"fmt"
-	"net"
-	"net/http"
-	"os/exec"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/sashabaranov/go-openai"
 )
 
 type Client struct {
-	config         ClientConfig
-	logger         *Logger
-	openAIClient   *openai.Client
-	conversation   []openai.ChatCompletionMessage
-	httpClient     *http.Client
+	config       ClientConfig
+	logger       *Logger
+	backend      Backend
+	conversation []openai.ChatCompletionMessage
+	tools        *ToolRegistry
+	// reasoningEffort, samplingParams, lastMetrics, retrieval, schema and
+	// lastValidation are guarded by mu
+	reasoningEffort string
+	samplingParams  SamplingParams
+	lastMetrics     StreamMetrics
+	retrieval       bool
+	schema          *JSONSchema
+	lastValidation  *SchemaValidation
+	// index and indexErr are guarded by indexMu, since loading the index
+	// must not hold up the settings behind mu
+	index          *VectorIndex
+	indexErr       error
+	indexMu        sync.Mutex
+	estimates      estimateCache
 	lastResponseID *string
 	mu             sync.Mutex
 	cancelled      atomic.Bool
 	cancelChan     chan struct{}
+	requestCancel  context.CancelFunc
 	ctx            context.Context
 	cancel         context.CancelFunc
 }
 
+// NewClientWithConfig creates a client for the LM Studio server described by config
 func NewClientWithConfig(ctx context.Context, config ClientConfig, logChannel chan string) (*Client, error) {
+	config = config.withDefaults()
 	if err := ValidateClientConfig(config); err != nil {
 		return nil, fmt.Errorf("invalid client configuration: %w", err)
 	}
 
-	if ctx == nil {
-		ctx = context.Background()
+	logger := NewLogger(logChannel)
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.TLS.InsecureSkipVerify {
+		logger.Warn("TLS certificate verification is disabled for %s", config.GetFullURL())
 	}
 
-	ctx, cancel := context.WithCancel(ctx)
-
-	openaiConfig := openai.DefaultConfig("dummy-key") // LM Studio doesn't need real API key
-	openaiConfig.BaseURL = config.GetAPIURL()         // LM Studio OpenAI-compatible endpoint
+	// The transport of httpClient sets the Authorization header, LM Studio
+	// itself accepts any key
+	openaiConfig := openai.DefaultConfig(config.ResolveAPIKey())
+	openaiConfig.BaseURL = config.GetAPIURL() // LM Studio OpenAI-compatible endpoint
+	openaiConfig.HTTPClient = httpClient
 
 	openaiClient := openai.NewClientWithConfig(openaiConfig)
 
-	// HTTP client for /v1/responses endpoint with proper connection pooling
-	transport := &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
-		TLSHandshakeTimeout: 10 * time.Second,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
+	backend, err := NewLMStudioBackend(config, httpClient, openaiClient, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientWithBackend(ctx, config, backend, logger)
+}
+
+// NewClientWithBackend creates a client that drives the given backend
+func NewClientWithBackend(ctx context.Context, config ClientConfig, backend Backend, logger *Logger) (*Client, error) {
+	config = config.withDefaults()
+	if err := ValidateClientConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid client configuration: %w", err)
 	}
 
-	httpClient := &http.Client{
-		Timeout:   config.HTTPTimeout,
-		Transport: transport,
+	if backend == nil {
+		return nil, fmt.Errorf("backend cannot be nil")
 	}
 
-	if _, err := exec.LookPath("lms"); err != nil {
-		cancel()
-		return nil, fmt.Errorf("lms command not found: %w", err)
+	if logger == nil {
+		logger = NewLogger(nil)
 	}
 
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
 	client := &Client{
-		config:         config,
-		logger:         NewLogger(logChannel),
-		openAIClient:   openaiClient,
-		conversation:   make([]openai.ChatCompletionMessage, 0),
-		httpClient:     httpClient,
-		lastResponseID: nil,
-		cancelChan:     make(chan struct{}, 1),
-		ctx:            ctx,
-		cancel:         cancel,
+		config:          config,
+		logger:          logger,
+		backend:         backend,
+		conversation:    make([]openai.ChatCompletionMessage, 0),
+		tools:           NewToolRegistry(),
+		reasoningEffort: config.ReasoningEffort,
+		retrieval:       config.Retrieval,
+		lastResponseID:  nil,
+		cancelChan:      make(chan struct{}, 1),
+		ctx:             ctx,
+		cancel:          cancel,
 	}
 
 	return client, nil
@@ -91,6 +119,101 @@ func (c *Client) GetConfig() ClientConfig {
 	return c.config
 }
 
+// GetBackend returns the backend the client drives
+func (c *Client) GetBackend() Backend {
+	return c.backend
+}
+
+// ReasoningEffort returns the reasoning effort used for new requests
+func (c *Client) ReasoningEffort() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reasoningEffort
+}
+
+// SetReasoningEffort changes the reasoning effort used for new requests
+func (c *Client) SetReasoningEffort(effort string) error {
+	if err := ValidateReasoningEffort(effort); err != nil {
+		return fmt.Errorf("invalid reasoning effort: %w", err)
+	}
+
+	c.mu.Lock()
+	c.reasoningEffort = effort
+	c.mu.Unlock()
+	c.logger.Info("Reasoning effort set to %s", effort)
+	return nil
+}
+
+// SamplingParams returns the sampling parameters used for new requests
+func (c *Client) SamplingParams() SamplingParams {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.samplingParams
+}
+
+// SetSamplingParams changes the sampling parameters used for new requests
+func (c *Client) SetSamplingParams(params SamplingParams) error {
+	if err := ValidateSamplingParams(params); err != nil {
+		return fmt.Errorf("invalid sampling parameters: %w", err)
+	}
+
+	c.mu.Lock()
+	c.samplingParams = params
+	c.mu.Unlock()
+	return nil
+}
+
+// LastMetrics returns the token usage and timings of the last response
+func (c *Client) LastMetrics() StreamMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastMetrics
+}
+
+// Schema returns the JSON Schema outputs must follow, or nil
+func (c *Client) Schema() *JSONSchema {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.schema
+}
+
+// SetSchema requests structured output following schema for new requests.
+// A nil schema returns to free-form text.
+func (c *Client) SetSchema(schema *JSONSchema) {
+	c.mu.Lock()
+	c.schema = schema
+	c.lastValidation = nil
+	c.mu.Unlock()
+
+	if schema != nil {
+		c.logger.Info("Structured output enabled with schema %s", schema.Name)
+	} else {
+		c.logger.Info("Structured output disabled")
+	}
+}
+
+// LastValidation returns the schema validation of the last response, or nil
+// when no schema was set
+func (c *Client) LastValidation() *SchemaValidation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastValidation
+}
+
+// Tools returns the registry of tools offered to the model
+func (c *Client) Tools() *ToolRegistry {
+	return c.tools
+}
+
+// RegisterTool makes a Go function callable by the model
+func (c *Client) RegisterTool(def ToolDefinition) error {
+	if err := c.tools.Register(def); err != nil {
+		return fmt.Errorf("invalid tool: %w", err)
+	}
+	c.logger.Info("Registered tool: %s", def.Name)
+	return nil
+}
+
 // GetLogger returns the client logger
 func (c *Client) GetLogger() *Logger {
 	return c.logger
@@ -113,15 +236,30 @@ func (c *Client) TruncateConversation(maxLength int) {
 		maxLength = MaxConversationLength
 	}
 
-	if len(c.conversation) > maxLength {
+	if len(c.conversation) <= maxLength {
+		return
+	}
+
+	// Keep the system message, the cut only applies to the rest of the history
+	if c.conversation[0].Role == openai.ChatMessageRoleSystem && maxLength > 1 {
+		rest := c.conversation[len(c.conversation)-(maxLength-1):]
+		c.conversation = append(c.conversation[:1], rest...)
+	} else {
 		c.conversation = c.conversation[len(c.conversation)-maxLength:]
-		c.ClearResponseHistory()
-		c.logger.Info("Truncated conversation history to %d messages", maxLength)
 	}
+	c.ClearResponseHistory()
+	c.logger.Info("Truncated conversation history to %d messages", maxLength)
 }
 
 func (c *Client) CancelRequest() {
 	c.cancelled.Store(true)
+
+	c.mu.Lock()
+	if c.requestCancel != nil {
+		c.requestCancel()
+	}
+	c.mu.Unlock()
+
 	select {
 	case c.cancelChan <- struct{}{}:
 	default:
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const compactionInstructions = "You summarize conversations between a user and an assistant. " +
+	"Write a concise summary of the conversation below that keeps every fact, decision, " +
+	"code identifier, error message and open question needed to continue it. " +
+	"Reply with the summary only."
+
+// SummaryMessagePrefix starts the synthetic message that replaces compacted turns
+const SummaryMessagePrefix = "Summary of the earlier conversation:\n\n"
+
+// shouldCompact reports whether the conversation is close enough to budget
+// to be compacted
+func (c *Client) shouldCompact(budget int) bool {
+	return c.config.Compact && budget > 0 &&
+		float64(c.ConversationTokens()) > float64(budget)*CompactionThresholdRatio
+}
+
+// compactionRange returns the slice of the conversation to summarize. The
+// system message and the most recent turns are kept, and the kept tail
+// starts at an assistant message so the synthetic user message is followed
+// by a reply.
+func (c *Client) compactionRange() (start, end int) {
+	if len(c.conversation) > 0 && c.conversation[0].Role == openai.ChatMessageRoleSystem {
+		start = 1
+	}
+
+	end = len(c.conversation) - CompactionKeepMessages
+	for end > start && end < len(c.conversation)-1 && c.conversation[end].Role != openai.ChatMessageRoleAssistant {
+		end++
+	}
+	if end >= len(c.conversation)-1 || end-start < 2 {
+		return start, start
+	}
+	return start, end
+}
+
+// CompactConversation asks the model to summarize the oldest turns and
+// replaces them with a single synthetic message. The summary is also passed
+// to callback with the ContentTypeSummary content type.
+func (c *Client) CompactConversation(ctx context.Context, modelID string, callback func(string, string)) error {
+	start, end := c.compactionRange()
+	if start == end {
+		return nil
+	}
+
+	var transcript strings.Builder
+	for _, msg := range c.conversation[start:end] {
+		fmt.Fprintf(&transcript, "%s: %s\n\n", msg.Role, messageText(msg))
+	}
+
+	maxTokens := MaxSummaryTokens
+	temperature := CompactionTemperature
+	req := ResponseRequest{
+		SamplingParams: SamplingParams{
+			Temperature:     &temperature,
+			MaxOutputTokens: &maxTokens,
+		},
+		Model: modelID,
+		Input: []InputMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: TextContent(compactionInstructions)},
+			{Role: openai.ChatMessageRoleUser, Content: TextContent(transcript.String())},
+		},
+	}
+
+	var summary strings.Builder
+	_, err := c.backend.StreamChat(ctx, req, func(text, contentType string) {
+		if contentType == ContentTypeOutput {
+			summary.WriteString(text)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("summarize conversation: %w", err)
+	}
+
+	text := strings.TrimSpace(summary.String())
+	if text == "" {
+		return fmt.Errorf("summarize conversation: model returned an empty summary")
+	}
+
+	summaryMessage := openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleUser,
+		Content: SummaryMessagePrefix + text,
+	}
+
+	compacted := make([]openai.ChatCompletionMessage, 0, len(c.conversation)-(end-start)+1)
+	compacted = append(compacted, c.conversation[:start]...)
+	compacted = append(compacted, summaryMessage)
+	compacted = append(compacted, c.conversation[end:]...)
+	c.conversation = compacted
+	c.ClearResponseHistory()
+
+	c.logger.Info("Compacted %d messages into a %d char summary", end-start, len(text))
+	callback(text, ContentTypeSummary)
+	return nil
+}
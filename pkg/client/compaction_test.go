@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// fakeBackend answers every request through streamChat
+type fakeBackend struct {
+	loaded     []LMSLoadedListItem
+	mu         sync.Mutex
+	requests   []ResponseRequest
+	streamChat func(ctx context.Context, req ResponseRequest, callback func(string, string)) (StreamResult, error)
+}
+
+func (f *fakeBackend) Name() string                                          { return "fake" }
+func (f *fakeBackend) GetStatus() (Status, error)                            { return StatusOn, nil }
+func (f *fakeBackend) GetDownloadedModels() ([]LMSDownloadedListItem, error) { return nil, nil }
+func (f *fakeBackend) GetLoadedModels() ([]LMSLoadedListItem, error)         { return f.loaded, nil }
+func (f *fakeBackend) LoadModel(string, LoadOptions) error                   { return nil }
+func (f *fakeBackend) UnloadModel(string) error                              { return nil }
+func (f *fakeBackend) UnloadAllModels() error                                { return nil }
+
+func (f *fakeBackend) StreamChat(ctx context.Context, req ResponseRequest, callback func(string, string)) (StreamResult, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+	return f.streamChat(ctx, req, callback)
+}
+
+func (f *fakeBackend) requestCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+// newCompactingClient returns a client whose conversation is due for
+// compaction on the next message
+func newCompactingClient(t *testing.T, backend *fakeBackend) *Client {
+	t.Helper()
+	backend.loaded = []LMSLoadedListItem{{Identifier: "model", ContextLength: 2000}}
+
+	config := DefaultClientConfig()
+	config.Compact = true
+	c, err := NewClientWithBackend(context.Background(), config, backend, nil)
+	if err != nil {
+		t.Fatalf("NewClientWithBackend() error = %v", err)
+	}
+
+	turn := strings.Repeat("word ", 80)
+	for i := 0; i < 4; i++ {
+		c.conversation = append(c.conversation,
+			openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: turn},
+			openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: turn},
+		)
+	}
+	return c
+}
+
+func TestCompactionSummarizesOldTurns(t *testing.T) {
+	backend := &fakeBackend{}
+	backend.streamChat = func(ctx context.Context, req ResponseRequest, callback func(string, string)) (StreamResult, error) {
+		callback("short summary", ContentTypeOutput)
+		return StreamResult{}, nil
+	}
+	c := newCompactingClient(t, backend)
+
+	var summary string
+	err := c.SendMessageStreamWithModel(context.Background(), "next question", "model", func(text, contentType string) {
+		if contentType == ContentTypeSummary {
+			summary = text
+		}
+	})
+	if err != nil {
+		t.Fatalf("SendMessageStreamWithModel() error = %v", err)
+	}
+	if summary != "short summary" {
+		t.Errorf("summary = %q, want %q", summary, "short summary")
+	}
+	if got := backend.requestCount(); got != 2 {
+		t.Errorf("backend got %d requests, want the summary and the message", got)
+	}
+	if got := messageText(c.conversation[0]); got != SummaryMessagePrefix+"short summary" {
+		t.Errorf("first message = %q, want the summary", got)
+	}
+}
+
+func TestCancelDuringCompaction(t *testing.T) {
+	backend := &fakeBackend{}
+	var c *Client
+	backend.streamChat = func(ctx context.Context, req ResponseRequest, callback func(string, string)) (StreamResult, error) {
+		// Cancel while the summary is streaming
+		go c.CancelRequest()
+		select {
+		case <-ctx.Done():
+			return StreamResult{}, ctx.Err()
+		case <-time.After(5 * time.Second):
+			t.Error("cancelling did not stop the compaction request")
+			return StreamResult{}, nil
+		}
+	}
+	c = newCompactingClient(t, backend)
+
+	err := c.SendMessageStreamWithModel(context.Background(), "next question", "model", func(string, string) {})
+	if err == nil || err.Error() != "request cancelled by user" {
+		t.Fatalf("SendMessageStreamWithModel() error = %v, want the request to be cancelled", err)
+	}
+	if got := backend.requestCount(); got != 1 {
+		t.Errorf("backend got %d requests, want only the cancelled summary", got)
+	}
+	if !c.IsCancelled() {
+		t.Error("IsCancelled() = false after cancelling")
+	}
+}
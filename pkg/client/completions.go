@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// streamChatCompletions streams a response from /v1/chat/completions. It
+// produces the same callbacks as the responses stream, so callers cannot
+// tell the two paths apart.
+func (b *LMStudioBackend) streamChatCompletions(ctx context.Context, req ResponseRequest, callback func(string, string)) (StreamResult, error) {
+	chatReq := openai.ChatCompletionRequest{
+		Model:    req.Model,
+		Messages: toChatCompletionMessages(req.Input),
+		Stream:   true,
+		StreamOptions: &openai.StreamOptions{
+			IncludeUsage: true,
+		},
+		Stop: req.Stop,
+		Seed: req.Seed,
+	}
+	if req.Temperature != nil {
+		chatReq.Temperature = float32(*req.Temperature)
+		if chatReq.Temperature == 0 {
+			// go-openai omits a zero temperature, this is its documented workaround
+			chatReq.Temperature = math.SmallestNonzeroFloat32
+		}
+	}
+	if req.TopP != nil {
+		chatReq.TopP = float32(*req.TopP)
+	}
+	if req.MaxOutputTokens != nil {
+		chatReq.MaxTokens = *req.MaxOutputTokens
+	}
+	if req.Reasoning != nil {
+		chatReq.ReasoningEffort = req.Reasoning.Effort
+	}
+	if req.Text != nil && req.Text.Format != nil && req.Text.Format.Type == TextFormatJSONSchema {
+		chatReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   req.Text.Format.Name,
+				Schema: req.Text.Format.Schema,
+				Strict: req.Text.Format.Strict,
+			},
+		}
+	}
+	for _, tool := range req.Tools {
+		chatReq.Tools = append(chatReq.Tools, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+
+	timer := newStreamTimer()
+	callback = timer.wrap(callback)
+
+	stream, err := b.openAIClient.CreateChatCompletionStream(ctx, chatReq)
+	if err != nil {
+		var reqErr *openai.RequestError
+		if errors.As(err, &reqErr) {
+			return StreamResult{}, &APIError{
+				StatusCode: reqErr.HTTPStatusCode,
+				Status:     reqErr.HTTPStatus,
+				Body:       string(reqErr.Body),
+			}
+		}
+		return StreamResult{}, fmt.Errorf("create chat completion stream: %w", err)
+	}
+	defer stream.Close()
+
+	// Tool call deltas are keyed by their index in the choice
+	var toolCalls []*ToolCall
+	var usage Usage
+
+	for {
+		if ctx.Err() != nil {
+			return StreamResult{}, fmt.Errorf("request cancelled by user")
+		}
+
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return StreamResult{}, fmt.Errorf("receive chat completion chunk: %w", err)
+		}
+
+		// Only the final chunk carries usage
+		if chunk.Usage != nil {
+			usage.InputTokens = chunk.Usage.PromptTokens
+			usage.OutputTokens = chunk.Usage.CompletionTokens
+			if chunk.Usage.CompletionTokensDetails != nil {
+				usage.ReasoningTokens = chunk.Usage.CompletionTokensDetails.ReasoningTokens
+			}
+		}
+
+		for _, choice := range chunk.Choices {
+			if choice.Delta.ReasoningContent != "" {
+				callback(choice.Delta.ReasoningContent, ContentTypeReasoning)
+			}
+			if choice.Delta.Content != "" {
+				callback(choice.Delta.Content, ContentTypeOutput)
+			}
+			for _, delta := range choice.Delta.ToolCalls {
+				index := len(toolCalls)
+				if delta.Index != nil {
+					index = *delta.Index
+				}
+				for len(toolCalls) <= index {
+					toolCalls = append(toolCalls, &ToolCall{})
+				}
+				call := toolCalls[index]
+				if delta.ID != "" {
+					call.CallID = delta.ID
+					call.ItemID = delta.ID
+				}
+				if delta.Function.Name != "" {
+					call.Name = delta.Function.Name
+				}
+				call.Arguments += delta.Function.Arguments
+			}
+		}
+	}
+
+	// Chat completions are stateless, there is no response ID to chain
+	result := StreamResult{Metrics: timer.metrics(usage)}
+	for _, call := range toolCalls {
+		result.ToolCalls = append(result.ToolCalls, *call)
+	}
+	return result, nil
+}
+
+// toChatCompletionMessages converts responses input into chat messages.
+// Consecutive function calls become one assistant message with tool calls
+// and their outputs become tool messages.
+func toChatCompletionMessages(input []InputMessage) []openai.ChatCompletionMessage {
+	messages := make([]openai.ChatCompletionMessage, 0, len(input))
+	for _, msg := range input {
+		switch msg.Type {
+		case InputTypeFunctionCall:
+			toolCall := openai.ToolCall{
+				ID:   msg.CallID,
+				Type: openai.ToolTypeFunction,
+				Function: openai.FunctionCall{
+					Name:      msg.Name,
+					Arguments: msg.Arguments,
+				},
+			}
+			last := len(messages) - 1
+			if last >= 0 && messages[last].Role == openai.ChatMessageRoleAssistant && len(messages[last].ToolCalls) > 0 {
+				messages[last].ToolCalls = append(messages[last].ToolCalls, toolCall)
+			} else {
+				messages = append(messages, openai.ChatCompletionMessage{
+					Role:      openai.ChatMessageRoleAssistant,
+					ToolCalls: []openai.ToolCall{toolCall},
+				})
+			}
+		case InputTypeFunctionCallOutput:
+			messages = append(messages, openai.ChatCompletionMessage{
+				Role:       openai.ChatMessageRoleTool,
+				Content:    msg.Output,
+				ToolCallID: msg.CallID,
+			})
+		default:
+			if msg.Content.HasImages() {
+				messages = append(messages, openai.ChatCompletionMessage{
+					Role:         msg.Role,
+					MultiContent: chatMessageParts(msg.Content),
+				})
+				break
+			}
+			messages = append(messages, openai.ChatCompletionMessage{
+				Role:    msg.Role,
+				Content: msg.Content.Text(),
+			})
+		}
+	}
+	return messages
+}
@@ -1,6 +1,10 @@
 package client
 
-import "time"
+import (
+	"net"
+	"strings"
+	"time"
+)
 
 const (
 	DefaultLogChannelSize    = 100
@@ -14,11 +18,41 @@ const (
 	DefaultLMStudioPort   = "1234"
 	DefaultLMStudioHost   = "localhost"
 	DefaultLMStudioScheme = "http"
+	DefaultRESTTimeout    = 10 * time.Second
+	DefaultProbeTimeout   = 3 * time.Second
 
 	MaxSystemMessageLength = 10000
 	MaxChatMessageLength   = 50000
 	MaxConversationLength  = 100
 
+	CharsPerTokenEstimate       = 4
+	MessageTokenOverhead        = 4 // Role and separator tokens added per message
+	DefaultContextReserveTokens = 1024
+
+	CompactionThresholdRatio = 0.8 // Compact once history fills this share of the budget
+	CompactionKeepMessages   = 4   // Most recent messages never summarized
+	CompactionTemperature    = 0.2
+	MaxSummaryTokens         = 1024
+
+	MaxImageAttachmentSize = 10 << 20 // 10 MB
+	ImageTokenEstimate     = 768      // Rough token cost of one attached image
+
+	EmbeddingBatchSize = 32
+	IndexChunkLines    = 40
+	IndexChunkOverlap  = 8
+	MaxIndexFileSize   = 1 << 20 // 1 MB
+	MaxIndexChunks     = 20000
+	RetrievalTopK      = 4
+	MinRetrievalScore  = 0.25
+
+	MaxTemperature   = 2.0
+	MaxStopSequences = 4
+
+	MaxToolCallRounds          = 8
+	MaxToolOutputDisplayLength = 500
+
+	MaxConcurrentEstimates = 4 // lms processes estimating at once
+
 	DefaultTickInterval      = 5 * time.Second
 	DefaultEstimateInterval  = 5 * time.Minute
 	DefaultAnimationInterval = 80 * time.Millisecond
@@ -30,35 +64,138 @@ const (
 	MaxHostnameLength = 253
 	MaxModelIDLength  = 256
 
+	DefaultAPIKeyEnv = "LAZYLMS_API_KEY"
+
 	OpenAIEndpointSuffix     = "/v1"
 	DefaultEmbeddingModelKey = "text-embedding-nomic-embed-text-v1.5"
 	EstimateSuccessMessage   = "Estimate: This model may be loaded based on your resource guardrails settings."
 
 	HTTPStatusOK            = 200
 	HTTPStatusBadRequest    = 400
+	HTTPStatusNotFound      = 404
 	HTTPStatusInternalError = 500
+	MaxErrorBodyLength      = 4096
+)
+
+// Model APIs used to list and manage models
+const (
+	ModelAPIREST = "rest" // LM Studio's native /api/v0 REST endpoints
+	ModelAPICLI  = "cli"  // The lms command line tool
+)
+
+// Chat APIs used to stream responses
+const (
+	ChatAPIAuto        = "auto"        // /v1/responses, falling back to chat completions on 404
+	ChatAPIResponses   = "responses"   // Always use /v1/responses
+	ChatAPICompletions = "completions" // Always use /v1/chat/completions
+)
+
+// Reasoning effort levels for thinking models
+const (
+	ReasoningEffortOff    = "off" // Omit the reasoning hint entirely
+	ReasoningEffortLow    = "low"
+	ReasoningEffortMedium = "medium"
+	ReasoningEffortHigh   = "high"
 )
 
 var ValidSchemes = []string{"http", "https"}
 
+var SupportedImageTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+var ValidModelAPIs = []string{ModelAPIREST, ModelAPICLI}
+
+var ValidChatAPIs = []string{ChatAPIAuto, ChatAPIResponses, ChatAPICompletions}
+
+// ValidReasoningEfforts is ordered from least to most effort
+var ValidReasoningEfforts = []string{ReasoningEffortOff, ReasoningEffortLow, ReasoningEffortMedium, ReasoningEffortHigh}
+
 type ClientConfig struct {
-	Host           string
-	Port           string
-	Scheme         string
-	HTTPTimeout    time.Duration
-	MaxRetries     int
-	LogChannelSize int
+	Host            string
+	Port            string
+	Scheme          string
+	HTTPTimeout     time.Duration
+	MaxRetries      int
+	LogChannelSize  int
+	ModelAPI        string
+	ChatAPI         string
+	APIKey          string            // Sent as a bearer token, takes precedence over APIKeyEnv
+	APIKeyEnv       string            // Environment variable holding the API key
+	Headers         map[string]string // Extra headers sent with every request
+	TLS             TLSOptions
+	ReasoningEffort string
+	Compact         bool // Summarize old turns instead of dropping them
+	Stateful        bool // Store responses on the server and send only new turns
+	EmbeddingModel  string
+	Retrieval       bool // Add relevant chunks from the vector index to prompts
+	Tools           bool // Let models call the built-in tools
+	DefaultModel    string
+	SystemPrompt    string
+	UI              UIOptions
+}
+
+// UIOptions configure the TUI
+type UIOptions struct {
+	HideReasoning bool // Leave reasoning out of chat messages
+	MaxLogLines   int
 }
 
 func DefaultClientConfig() ClientConfig {
 	return ClientConfig{
-		Host:           DefaultLMStudioHost,
-		Port:           DefaultLMStudioPort,
-		Scheme:         DefaultLMStudioScheme,
-		HTTPTimeout:    DefaultHTTPTimeout,
-		MaxRetries:     DefaultMaxRetries,
-		LogChannelSize: DefaultLogChannelSize,
+		Host:            DefaultLMStudioHost,
+		Port:            DefaultLMStudioPort,
+		Scheme:          DefaultLMStudioScheme,
+		HTTPTimeout:     DefaultHTTPTimeout,
+		MaxRetries:      DefaultMaxRetries,
+		LogChannelSize:  DefaultLogChannelSize,
+		ModelAPI:        ModelAPIREST,
+		ChatAPI:         ChatAPIAuto,
+		APIKeyEnv:       DefaultAPIKeyEnv,
+		ReasoningEffort: ReasoningEffortOff,
+		EmbeddingModel:  DefaultEmbeddingModelKey,
+		UI: UIOptions{
+			MaxLogLines: DefaultMaxLogLines,
+		},
+	}
+}
+
+// withDefaults fills in settings left zero by configurations written before
+// the settings existed
+func (c ClientConfig) withDefaults() ClientConfig {
+	if c.EmbeddingModel == "" {
+		c.EmbeddingModel = DefaultEmbeddingModelKey
+	}
+	if c.UI.MaxLogLines == 0 {
+		c.UI.MaxLogLines = DefaultMaxLogLines
+	}
+	return c
+}
+
+// WithServer returns the configuration with the connection settings of
+// server, keeping the chat and UI settings
+func (c ClientConfig) WithServer(server ClientConfig) ClientConfig {
+	c.Host = server.Host
+	c.Port = server.Port
+	c.Scheme = server.Scheme
+	c.HTTPTimeout = server.HTTPTimeout
+	c.MaxRetries = server.MaxRetries
+	c.ModelAPI = server.ModelAPI
+	c.ChatAPI = server.ChatAPI
+	c.APIKey = server.APIKey
+	c.APIKeyEnv = server.APIKeyEnv
+	c.Headers = server.Headers
+	c.TLS = server.TLS
+	return c
+}
+
+// IsLocal reports whether the server runs on this machine, which is the only
+// LM Studio the lms command can reach
+func (c ClientConfig) IsLocal() bool {
+	host := strings.ToLower(strings.Trim(c.Host, "[]"))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
 	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
 }
 
 func (c ClientConfig) GetFullURL() string {
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file names looked up in ConfigDir, in order
+var ConfigFileNames = []string{"config.toml", "config.yaml", "config.yml"}
+
+// ConfigPathEnv overrides the config file location
+const ConfigPathEnv = "LAZYLMS_CONFIG_PATH"
+
+// ConfigFile holds the settings of a config file. Settings outside the
+// profiles section apply to every profile, a profile overrides them.
+//
+//	default_profile = "local"
+//
+//	[profiles.local]
+//	port = 1234
+//
+//	[profiles.workstation]
+//	host = "10.0.0.12"
+//	default_model = "qwen/qwen3-8b"
+//	ui.hide_reasoning = true
+//	headers.X-Organization = "research"
+type ConfigFile struct {
+	Path           string
+	DefaultProfile string
+	shared         profileSettings
+	profiles       map[string]profileSettings
+}
+
+// configDocument is the layout of a config file
+type configDocument struct {
+	DefaultProfile  string                     `toml:"default_profile" yaml:"default_profile"`
+	Profiles        map[string]profileSettings `toml:"profiles" yaml:"profiles"`
+	profileSettings `yaml:",inline"`
+}
+
+// profileSettings are the settings of a profile or of the whole file. Unset
+// settings are nil and leave the configuration alone.
+type profileSettings struct {
+	Host            *string           `toml:"host" yaml:"host"`
+	Port            *portSetting      `toml:"port" yaml:"port"`
+	Scheme          *string           `toml:"scheme" yaml:"scheme"`
+	Timeout         *time.Duration    `toml:"timeout" yaml:"timeout"`
+	MaxRetries      *int              `toml:"max_retries" yaml:"max_retries"`
+	ModelAPI        *string           `toml:"model_api" yaml:"model_api"`
+	ChatAPI         *string           `toml:"chat_api" yaml:"chat_api"`
+	APIKey          *string           `toml:"api_key" yaml:"api_key"`
+	APIKeyEnv       *string           `toml:"api_key_env" yaml:"api_key_env"`
+	Headers         map[string]string `toml:"headers" yaml:"headers"`
+	ReasoningEffort *string           `toml:"reasoning_effort" yaml:"reasoning_effort"`
+	Compact         *bool             `toml:"compact" yaml:"compact"`
+	Stateful        *bool             `toml:"stateful" yaml:"stateful"`
+	EmbeddingModel  *string           `toml:"embedding_model" yaml:"embedding_model"`
+	RAG             *bool             `toml:"rag" yaml:"rag"`
+	Tools           *bool             `toml:"tools" yaml:"tools"`
+	DefaultModel    *string           `toml:"default_model" yaml:"default_model"`
+	SystemPrompt    *string           `toml:"system_prompt" yaml:"system_prompt"`
+	TLS             struct {
+		CAFile             *string `toml:"ca_file" yaml:"ca_file"`
+		CertFile           *string `toml:"cert_file" yaml:"cert_file"`
+		KeyFile            *string `toml:"key_file" yaml:"key_file"`
+		ServerName         *string `toml:"server_name" yaml:"server_name"`
+		InsecureSkipVerify *bool   `toml:"insecure_skip_verify" yaml:"insecure_skip_verify"`
+	} `toml:"tls" yaml:"tls"`
+	UI struct {
+		HideReasoning *bool `toml:"hide_reasoning" yaml:"hide_reasoning"`
+		MaxLogLines   *int  `toml:"max_log_lines" yaml:"max_log_lines"`
+	} `toml:"ui" yaml:"ui"`
+}
+
+// portSetting is a port written as a number or a string. YAML converts
+// numbers to strings by itself, TOML needs help.
+type portSetting string
+
+func (p *portSetting) UnmarshalTOML(value any) error {
+	switch v := value.(type) {
+	case string:
+		*p = portSetting(v)
+	case int64:
+		*p = portSetting(strconv.FormatInt(v, 10))
+	default:
+		return fmt.Errorf("port: expected a number, got %v", value)
+	}
+	return nil
+}
+
+// apply sets the settings that are present on config
+func (s profileSettings) apply(config *ClientConfig) {
+	setIfPresent(&config.Host, s.Host)
+	if s.Port != nil {
+		config.Port = string(*s.Port)
+	}
+	setIfPresent(&config.Scheme, s.Scheme)
+	setIfPresent(&config.HTTPTimeout, s.Timeout)
+	setIfPresent(&config.MaxRetries, s.MaxRetries)
+	setIfPresent(&config.ModelAPI, s.ModelAPI)
+	setIfPresent(&config.ChatAPI, s.ChatAPI)
+	setIfPresent(&config.APIKey, s.APIKey)
+	setIfPresent(&config.APIKeyEnv, s.APIKeyEnv)
+	for name, value := range s.Headers {
+		config.Headers = withHeader(config.Headers, name, value)
+	}
+	setIfPresent(&config.ReasoningEffort, s.ReasoningEffort)
+	setIfPresent(&config.Compact, s.Compact)
+	setIfPresent(&config.Stateful, s.Stateful)
+	setIfPresent(&config.EmbeddingModel, s.EmbeddingModel)
+	setIfPresent(&config.Retrieval, s.RAG)
+	setIfPresent(&config.Tools, s.Tools)
+	setIfPresent(&config.DefaultModel, s.DefaultModel)
+	setIfPresent(&config.SystemPrompt, s.SystemPrompt)
+	setIfPresent(&config.TLS.CAFile, s.TLS.CAFile)
+	setIfPresent(&config.TLS.CertFile, s.TLS.CertFile)
+	setIfPresent(&config.TLS.KeyFile, s.TLS.KeyFile)
+	setIfPresent(&config.TLS.ServerName, s.TLS.ServerName)
+	setIfPresent(&config.TLS.InsecureSkipVerify, s.TLS.InsecureSkipVerify)
+	setIfPresent(&config.UI.HideReasoning, s.UI.HideReasoning)
+	setIfPresent(&config.UI.MaxLogLines, s.UI.MaxLogLines)
+}
+
+func setIfPresent[T any](dst *T, value *T) {
+	if value != nil {
+		*dst = *value
+	}
+}
+
+// DefaultConfigFilePath returns the config file to read: the path in
+// LAZYLMS_CONFIG_PATH, or the first existing file in ConfigDir. It returns an
+// empty path when there is none.
+func DefaultConfigFilePath() (string, error) {
+	if path := os.Getenv(ConfigPathEnv); path != "" {
+		return path, nil
+	}
+
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range ConfigFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// LoadConfigFile reads a TOML or YAML config file, chosen by its extension.
+// Unknown settings are rejected so that typos do not go unnoticed.
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("config file %s does not exist", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var document configDocument
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = decodeYAMLConfig(data, &document)
+	default:
+		err = decodeTOMLConfig(data, &document)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	file := &ConfigFile{
+		Path:           path,
+		DefaultProfile: document.DefaultProfile,
+		shared:         document.profileSettings,
+		profiles:       document.Profiles,
+	}
+	if file.profiles == nil {
+		file.profiles = make(map[string]profileSettings)
+	}
+
+	if file.DefaultProfile != "" {
+		if _, ok := file.profiles[file.DefaultProfile]; !ok {
+			return nil, fmt.Errorf("%s: default_profile %q is not defined", path, file.DefaultProfile)
+		}
+	}
+	return file, nil
+}
+
+func decodeTOMLConfig(data []byte, document *configDocument) error {
+	metadata, err := toml.Decode(string(data), document)
+	if err != nil {
+		return err
+	}
+	if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+		return fmt.Errorf("unknown setting %q", undecoded[0].String())
+	}
+	return nil
+}
+
+func decodeYAMLConfig(data []byte, document *configDocument) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(document); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// ProfileNames returns the defined profiles, sorted
+func (f *ConfigFile) ProfileNames() []string {
+	names := make([]string, 0, len(f.profiles))
+	for name := range f.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServerProfile is a profile of the config file, as offered by the server switcher
+type ServerProfile struct {
+	Name   string
+	Config ClientConfig
+	Err    error // Set when the profile does not apply cleanly
+}
+
+// Servers resolves every profile on top of the defaults
+func (f *ConfigFile) Servers() []ServerProfile {
+	names := f.ProfileNames()
+	servers := make([]ServerProfile, len(names))
+	for i, name := range names {
+		config := DefaultClientConfig()
+		err := f.Apply(&config, name)
+		if err == nil {
+			if err = ValidateClientConfig(config); err != nil {
+				err = fmt.Errorf("%s: profile %q: %w", f.Path, name, err)
+			}
+		}
+		servers[i] = ServerProfile{Name: name, Config: config, Err: err}
+	}
+	return servers
+}
+
+// Apply sets the shared settings and those of a profile on config. An
+// empty profile selects the default profile, if any. The result is not
+// validated, since command line flags may still complete it.
+func (f *ConfigFile) Apply(config *ClientConfig, profile string) error {
+	if profile == "" {
+		profile = f.DefaultProfile
+	}
+
+	f.shared.apply(config)
+	if profile == "" {
+		return nil
+	}
+
+	settings, ok := f.profiles[profile]
+	if !ok {
+		return fmt.Errorf("%s: unknown profile %q, defined profiles: %s", f.Path, profile, strings.Join(f.ProfileNames(), ", "))
+	}
+	settings.apply(config)
+	return nil
+}
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfigFile writes a config file with the given name to a temporary
+// directory and returns its path
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		profile string
+		check   func(t *testing.T, config ClientConfig)
+	}{
+		{
+			name: "toml profiles over shared settings",
+			file: "config.toml",
+			content: `
+timeout = "30s"
+default_profile = "work"
+headers.X-Team = "ml"
+
+[profiles.local]
+port = 1234
+
+[profiles.work]
+host = "10.0.0.12"
+port = "8080"
+max_retries = 5
+compact = true
+ui.hide_reasoning = true
+headers.X-Organization = "research"
+tls.server_name = "llm.internal"
+`,
+			check: func(t *testing.T, config ClientConfig) {
+				want := DefaultClientConfig()
+				want.HTTPTimeout = 30 * time.Second
+				want.Host = "10.0.0.12"
+				want.Port = "8080"
+				want.MaxRetries = 5
+				want.Compact = true
+				want.UI.HideReasoning = true
+				want.Headers = map[string]string{"X-Team": "ml", "X-Organization": "research"}
+				want.TLS.ServerName = "llm.internal"
+				if !reflect.DeepEqual(config, want) {
+					t.Errorf("config = %+v, want %+v", config, want)
+				}
+			},
+		},
+		{
+			name:    "toml numeric port",
+			file:    "config.toml",
+			content: "[profiles.local]\nport = 4321\n",
+			profile: "local",
+			check: func(t *testing.T, config ClientConfig) {
+				if config.Port != "4321" {
+					t.Errorf("Port = %q, want %q", config.Port, "4321")
+				}
+			},
+		},
+		{
+			name: "toml multi-line strings and comments",
+			file: "config.toml",
+			content: `
+system_prompt = """
+Use C# here. # Not a comment
+Don't guess."""
+default_model = 'qwen/qwen3-8b' # a comment
+`,
+			check: func(t *testing.T, config ClientConfig) {
+				if want := "Use C# here. # Not a comment\nDon't guess."; config.SystemPrompt != want {
+					t.Errorf("SystemPrompt = %q, want %q", config.SystemPrompt, want)
+				}
+				if config.DefaultModel != "qwen/qwen3-8b" {
+					t.Errorf("DefaultModel = %q, want %q", config.DefaultModel, "qwen/qwen3-8b")
+				}
+			},
+		},
+		{
+			name:    "toml inline tables",
+			file:    "config.toml",
+			content: "tls = { ca_file = \"/etc/ca.pem\" }\nheaders = { X-A = \"1\", X-B = \"2\" }\n",
+			check: func(t *testing.T, config ClientConfig) {
+				if config.TLS.CAFile != "/etc/ca.pem" {
+					t.Errorf("TLS.CAFile = %q, want %q", config.TLS.CAFile, "/etc/ca.pem")
+				}
+				if want := map[string]string{"X-A": "1", "X-B": "2"}; !reflect.DeepEqual(config.Headers, want) {
+					t.Errorf("Headers = %v, want %v", config.Headers, want)
+				}
+			},
+		},
+		{
+			name: "yaml comments and plain values",
+			file: "config.yaml",
+			content: `
+system_prompt: Use C# here # trailing comment
+default_model: don't-guess # another comment
+compact: yes
+timeout: 2m
+port: 1234
+`,
+			check: func(t *testing.T, config ClientConfig) {
+				if config.SystemPrompt != "Use C# here" {
+					t.Errorf("SystemPrompt = %q, want %q", config.SystemPrompt, "Use C# here")
+				}
+				if config.DefaultModel != "don't-guess" {
+					t.Errorf("DefaultModel = %q, want %q", config.DefaultModel, "don't-guess")
+				}
+				if !config.Compact || config.HTTPTimeout != 2*time.Minute || config.Port != "1234" {
+					t.Errorf("Compact, HTTPTimeout, Port = %v, %v, %q", config.Compact, config.HTTPTimeout, config.Port)
+				}
+			},
+		},
+		{
+			name: "yaml block scalars, anchors and flow mappings",
+			file: "config.yml",
+			content: `
+profiles:
+  base: &base
+    host: 10.0.0.12
+    ui: {hide_reasoning: true}
+  work:
+    <<: *base
+    system_prompt: |
+      First line.
+      Second line.
+`,
+			profile: "work",
+			check: func(t *testing.T, config ClientConfig) {
+				if config.Host != "10.0.0.12" || !config.UI.HideReasoning {
+					t.Errorf("Host, UI.HideReasoning = %q, %v, want the merged anchor", config.Host, config.UI.HideReasoning)
+				}
+				if want := "First line.\nSecond line.\n"; config.SystemPrompt != want {
+					t.Errorf("SystemPrompt = %q, want %q", config.SystemPrompt, want)
+				}
+			},
+		},
+		{
+			name:    "empty yaml",
+			file:    "config.yaml",
+			content: "# nothing here\n",
+			check: func(t *testing.T, config ClientConfig) {
+				if !reflect.DeepEqual(config, DefaultClientConfig()) {
+					t.Errorf("config = %+v, want the defaults", config)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := LoadConfigFile(writeConfigFile(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatalf("LoadConfigFile() error = %v", err)
+			}
+			config := DefaultClientConfig()
+			if err := file.Apply(&config, tt.profile); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			tt.check(t, config)
+		})
+	}
+}
+
+func TestLoadConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{name: "toml unknown setting", file: "config.toml", content: "[profiles.a]\nhots = \"x\"\n", want: `unknown setting "profiles.a.hots"`},
+		{name: "toml wrong type", file: "config.toml", content: "compact = \"yes\"\n", want: "compact"},
+		{name: "toml bad duration", file: "config.toml", content: "timeout = \"soon\"\n", want: "timeout"},
+		{name: "toml duplicate key", file: "config.toml", content: "host = \"a\"\nhost = \"b\"\n", want: "host"},
+		{name: "toml syntax", file: "config.toml", content: "host = \n", want: "config.toml"},
+		{name: "yaml unknown setting", file: "config.yaml", content: "profiles:\n  a:\n    hots: x\n", want: "field hots not found"},
+		{name: "yaml duplicate key", file: "config.yaml", content: "host: a\nhost: b\n", want: "already defined"},
+		{name: "yaml list for a string", file: "config.yaml", content: "host:\n  - a\n", want: "cannot unmarshal !!seq into string"},
+		{name: "undefined default profile", file: "config.toml", content: "default_profile = \"work\"\n", want: `default_profile "work" is not defined`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfigFile(writeConfigFile(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadConfigFile() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigFileApplyDoesNotValidate(t *testing.T) {
+	// The key may still come from the command line
+	file, err := LoadConfigFile(writeConfigFile(t, "config.toml", "tls.cert_file = \"client.pem\"\n"))
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+
+	config := DefaultClientConfig()
+	if err := file.Apply(&config, ""); err != nil {
+		t.Fatalf("Apply() error = %v, want the incomplete profile to be accepted", err)
+	}
+	if err := ValidateClientConfig(config); err == nil {
+		t.Error("ValidateClientConfig() error = nil for a certificate without a key")
+	}
+	config.TLS.KeyFile = "client.key"
+	if err := ValidateClientConfig(config); err != nil {
+		t.Errorf("ValidateClientConfig() error = %v once the key is given", err)
+	}
+}
+
+func TestConfigFileServers(t *testing.T) {
+	file, err := LoadConfigFile(writeConfigFile(t, "config.toml", `
+[profiles.good]
+host = "10.0.0.12"
+
+[profiles.bad]
+scheme = "ftp"
+`))
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+
+	if err := file.Apply(&ClientConfig{}, "missing"); err == nil || !strings.Contains(err.Error(), "defined profiles: bad, good") {
+		t.Errorf("Apply() with an unknown profile error = %v", err)
+	}
+
+	servers := file.Servers()
+	if len(servers) != 2 || servers[0].Name != "bad" || servers[1].Name != "good" {
+		t.Fatalf("Servers() = %+v, want bad and good", servers)
+	}
+	if servers[0].Err == nil {
+		t.Error("Servers() accepted a profile with an invalid scheme")
+	}
+	if servers[1].Err != nil || servers[1].Config.Host != "10.0.0.12" {
+		t.Errorf("Servers() good profile = %+v", servers[1])
+	}
+}
+
+func TestMaxLogLinesDefault(t *testing.T) {
+	config := DefaultClientConfig()
+	config.UI = UIOptions{HideReasoning: true}
+	c, err := NewClientWithBackend(context.Background(), config, stubBackend{}, nil)
+	if err != nil {
+		t.Fatalf("NewClientWithBackend() error = %v", err)
+	}
+	if lines := c.GetConfig().UI.MaxLogLines; lines != DefaultMaxLogLines {
+		t.Errorf("UI.MaxLogLines = %d, want %d", lines, DefaultMaxLogLines)
+	}
+
+	if err := ValidateClientConfig(config); err != nil {
+		t.Errorf("ValidateClientConfig() error = %v without max log lines", err)
+	}
+	config.UI.MaxLogLines = -1
+	if err := ValidateClientConfig(config); err == nil {
+		t.Error("ValidateClientConfig() error = nil for negative max log lines")
+	}
+}
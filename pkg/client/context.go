@@ -0,0 +1,79 @@
+package client
+
+import (
+	"unicode/utf8"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// EstimateTokens approximates the number of tokens in text. It is only used
+// to budget history, so a character based estimate is good enough.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + CharsPerTokenEstimate - 1) / CharsPerTokenEstimate
+}
+
+func estimateMessageTokens(msg openai.ChatCompletionMessage) int {
+	return EstimateTokens(messageText(msg)) + countImages(msg)*ImageTokenEstimate + MessageTokenOverhead
+}
+
+// ModelContextLength returns the context window of a loaded model, which is
+// the loaded context length when known and the model maximum otherwise
+func ModelContextLength(model LMSLoadedListItem) int {
+	if model.ContextLength > 0 {
+		return model.ContextLength
+	}
+	return model.MaxContextLength
+}
+
+// ConversationTokens returns the estimated token count of the conversation
+func (c *Client) ConversationTokens() int {
+	total := 0
+	for _, msg := range c.conversation {
+		total += estimateMessageTokens(msg)
+	}
+	return total
+}
+
+// ContextBudget returns how many tokens of history fit into a context
+// window of contextLength, leaving room for the response
+func (c *Client) ContextBudget(contextLength int) int {
+	reserve := DefaultContextReserveTokens
+	if maxTokens := c.SamplingParams().MaxOutputTokens; maxTokens != nil {
+		reserve = *maxTokens
+	}
+	// Never let the reserve take more than half of a small context window
+	reserve = min(reserve, contextLength/2)
+	return contextLength - reserve
+}
+
+// TruncateConversationToTokens drops the oldest messages until the estimated
+// size of the conversation fits into budget. The system message and the
+// latest message are always kept.
+func (c *Client) TruncateConversationToTokens(budget int) {
+	if budget <= 0 {
+		return
+	}
+
+	total := c.ConversationTokens()
+	if total <= budget {
+		return
+	}
+
+	start := 0
+	if len(c.conversation) > 0 && c.conversation[0].Role == openai.ChatMessageRoleSystem {
+		start = 1
+	}
+
+	dropped := 0
+	for len(c.conversation)-start-dropped > 1 && total > budget {
+		total -= estimateMessageTokens(c.conversation[start+dropped])
+		dropped++
+	}
+	if dropped == 0 {
+		return
+	}
+
+	c.conversation = append(c.conversation[:start], c.conversation[start+dropped:]...)
+	c.ClearResponseHistory()
+	c.logger.Info("Dropped %d messages to fit the context window (~%d/%d tokens)", dropped, total, budget)
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "", want: 0},
+		{text: "a", want: 1},
+		{text: "abcd", want: 1},
+		{text: "abcde", want: 2},
+		{text: "日本語の", want: 1}, // Counted in runes, not bytes
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestContextBudget(t *testing.T) {
+	maxTokens := 256
+	tests := []struct {
+		name          string
+		contextLength int
+		maxTokens     *int
+		want          int
+	}{
+		{name: "default reserve", contextLength: 8192, want: 8192 - DefaultContextReserveTokens},
+		{name: "max output tokens as reserve", contextLength: 8192, maxTokens: &maxTokens, want: 8192 - 256},
+		{name: "small window keeps half", contextLength: 1000, want: 500},
+		{name: "unknown window", contextLength: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t)
+			c.samplingParams.MaxOutputTokens = tt.maxTokens
+			if got := c.ContextBudget(tt.contextLength); got != tt.want {
+				t.Errorf("ContextBudget(%d) = %d, want %d", tt.contextLength, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateConversationToTokens(t *testing.T) {
+	// Every message is 10 tokens of text plus the per-message overhead
+	text := strings.Repeat("a", 10*CharsPerTokenEstimate)
+	perMessage := 10 + MessageTokenOverhead
+	message := func(role string) openai.ChatCompletionMessage {
+		return openai.ChatCompletionMessage{Role: role, Content: text}
+	}
+	system := openai.ChatMessageRoleSystem
+	user := openai.ChatMessageRoleUser
+	assistant := openai.ChatMessageRoleAssistant
+
+	tests := []struct {
+		name         string
+		conversation []string // Roles of the messages
+		budget       int
+		want         []string
+	}{
+		{name: "fits", conversation: []string{user, assistant, user}, budget: 3 * perMessage, want: []string{user, assistant, user}},
+		{name: "no budget", conversation: []string{user, assistant, user}, budget: 0, want: []string{user, assistant, user}},
+		{name: "drops oldest", conversation: []string{user, assistant, user}, budget: 2 * perMessage, want: []string{assistant, user}},
+		{name: "keeps system", conversation: []string{system, user, assistant, user}, budget: 2 * perMessage, want: []string{system, user}},
+		{name: "keeps latest over budget", conversation: []string{system, user, assistant, user}, budget: 1, want: []string{system, user}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t)
+			for _, role := range tt.conversation {
+				c.conversation = append(c.conversation, message(role))
+			}
+			responseID := "resp_1"
+			c.lastResponseID = &responseID
+
+			c.TruncateConversationToTokens(tt.budget)
+
+			var got []string
+			for _, msg := range c.conversation {
+				got = append(got, msg.Role)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("conversation = %v, want %v", got, tt.want)
+			}
+			// A shortened history cannot continue a stored response chain
+			dropped := len(tt.conversation) != len(tt.want)
+			if dropped != (c.GetLastResponseID() == nil) {
+				t.Errorf("response ID kept = %v after dropping messages = %v", c.GetLastResponseID() != nil, dropped)
+			}
+		})
+	}
+}
+
+// stubBackend satisfies Backend for tests that never reach the server
+type stubBackend struct{ Backend }
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	c, err := NewClientWithBackend(context.Background(), DefaultClientConfig(), stubBackend{}, nil)
+	if err != nil {
+		t.Fatalf("NewClientWithBackend() error = %v", err)
+	}
+	return c
+}
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// modelSearchURL is the Hugging Face model search that resolves search terms
+var modelSearchURL = "https://huggingface.co/api/models"
+
+// DownloadProgress is the state of a model download
+type DownloadProgress struct {
+	Percent    float64 // 0 to 100, negative until the download reports it
+	Downloaded int64   // Bytes downloaded, 0 when not reported
+	Total      int64   // Size of the download in bytes, 0 when not reported
+	Status     string  // Last message that was not a progress line
+}
+
+var (
+	// publisher/model, optionally pinned to a quantization with @
+	downloadKey     = regexp.MustCompile(`^[\w][\w.-]*/[\w.-]+(@[\w.-]+)?$`)
+	downloadPercent = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+	downloadSizes   = regexp.MustCompile(`(?i)([\d.,]+\s*[KMGT]?i?B)\s*/\s*([\d.,]+\s*[KMGT]?i?B)`)
+)
+
+// ParseDownloadProgress reads a progress line of lms get such as
+//
+//	⠼ 45.20% |█████████░░░░░░░░░░░| 2.31 GB / 5.12 GB | 12.3 MB/s | ETA 03:45
+//
+// It reports false for lines carrying neither a percentage nor sizes.
+func ParseDownloadProgress(line string) (DownloadProgress, bool) {
+	progress := DownloadProgress{Percent: -1}
+	found := false
+
+	if match := downloadSizes.FindStringSubmatch(line); match != nil {
+		downloaded, err1 := ParseMemorySize(match[1])
+		total, err2 := ParseMemorySize(match[2])
+		if err1 == nil && err2 == nil {
+			progress.Downloaded, progress.Total = downloaded, total
+			found = true
+			if total > 0 {
+				progress.Percent = 100 * float64(downloaded) / float64(total)
+			}
+		}
+	}
+	if match := downloadPercent.FindStringSubmatch(line); match != nil {
+		if percent, err := strconv.ParseFloat(match[1], 64); err == nil && percent <= 100 {
+			progress.Percent = percent
+			found = true
+		}
+	}
+	return progress, found
+}
+
+// DownloadJob is a model download running in the background
+type DownloadJob struct {
+	Model    string
+	progress chan DownloadProgress
+	cancel   context.CancelFunc
+	err      error // Set before progress is closed
+}
+
+// IsDownloadKey reports whether model names exactly one model, as
+// publisher/model with an optional @quantization or as a Hugging Face URL.
+// Anything else is a search term.
+func IsDownloadKey(model string) bool {
+	return strings.HasPrefix(model, "https://huggingface.co/") || downloadKey.MatchString(model)
+}
+
+// ValidateDownloadKey checks that model is a model key. lms get would pick
+// the best match of a search term without asking, so search terms go
+// through ResolveDownload first.
+func ValidateDownloadKey(model string) error {
+	if err := ValidateModelID(model); err != nil {
+		return err
+	}
+	if IsDownloadKey(model) {
+		return nil
+	}
+	return ValidationError{
+		Field:   "model_id",
+		Value:   model,
+		Message: "expected a model key such as qwen/qwen3-8b or a Hugging Face URL, resolve search terms first",
+	}
+}
+
+// ResolveDownload returns the model a search term stands for: the most
+// downloaded GGUF repository on Hugging Face that matches it. Model keys are
+// returned as they are. The caller shows or confirms the match before
+// downloading it.
+func ResolveDownload(ctx context.Context, term string) (string, error) {
+	term = strings.TrimSpace(term)
+	if err := ValidateModelID(term); err != nil {
+		return "", fmt.Errorf("invalid model: %w", err)
+	}
+	if IsDownloadKey(term) {
+		return term, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultRESTTimeout)
+	defer cancel()
+
+	query := url.Values{
+		"search":    {term},
+		"filter":    {"gguf"},
+		"sort":      {"downloads"},
+		"direction": {"-1"},
+		"limit":     {"1"},
+	}
+	// Not the server's client, its credentials are meant for LM Studio only
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", modelSearchURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("searching models: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != HTTPStatusOK {
+		return "", fmt.Errorf("searching models: %s", resp.Status)
+	}
+
+	var matches []struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&matches); err != nil {
+		return "", fmt.Errorf("Failed to parse search results: %w", err)
+	}
+	if len(matches) == 0 || !IsDownloadKey(matches[0].ID) {
+		return "", fmt.Errorf("no model matches %q", term)
+	}
+	return matches[0].ID, nil
+}
+
+// StartDownload downloads a model by key in the background. Search terms
+// are resolved with ResolveDownload first. Cancelling ctx or the job stops
+// it.
+func (c *Client) StartDownload(ctx context.Context, model string) (*DownloadJob, error) {
+	model = strings.TrimSpace(model)
+	if err := ValidateDownloadKey(model); err != nil {
+		return nil, fmt.Errorf("invalid model: %w", err)
+	}
+
+	downloader, ok := c.backend.(Downloader)
+	if !ok {
+		return nil, fmt.Errorf("downloading models: %w", ErrNotSupported)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	job := &DownloadJob{
+		Model:    model,
+		progress: make(chan DownloadProgress, 1),
+		cancel:   cancel,
+	}
+
+	c.logger.Info("Downloading model: %s", model)
+	go func() {
+		defer close(job.progress)
+		defer cancel()
+		job.err = downloader.DownloadModel(ctx, model, job.report)
+		switch {
+		case job.err == nil:
+			c.logger.Info("Downloaded model: %s", model)
+		case ctx.Err() != nil:
+			c.logger.Info("Cancelled download of %s", model)
+		default:
+			c.logger.Error("Failed to download model %s: %v", model, job.err)
+		}
+	}()
+	return job, nil
+}
+
+// report keeps only the latest update, so a slow reader never holds up the
+// download
+func (j *DownloadJob) report(progress DownloadProgress) {
+	select {
+	case <-j.progress:
+	default:
+	}
+	j.progress <- progress
+}
+
+// Progress delivers the latest progress and is closed when the download ends
+func (j *DownloadJob) Progress() <-chan DownloadProgress {
+	return j.progress
+}
+
+// Cancel stops the download
+func (j *DownloadJob) Cancel() {
+	j.cancel()
+}
+
+// Err is why the download failed, context.Canceled when it was cancelled.
+// It is only meaningful once Progress is closed.
+func (j *DownloadJob) Err() error {
+	return j.err
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseDownloadProgress(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want DownloadProgress
+		ok   bool
+	}{
+		{
+			name: "full progress line",
+			line: "⠼ 45.20% |█████████░░░░░░░░░░░| 2.31 GB / 5.12 GB | 12.3 MB/s | ETA 03:45",
+			want: DownloadProgress{Percent: 45.2, Downloaded: 2.31e9, Total: 5.12e9},
+			ok:   true,
+		},
+		{
+			name: "sizes only",
+			line: "512 MiB / 1 GiB",
+			want: DownloadProgress{Percent: 50, Downloaded: 512 << 20, Total: 1 << 30},
+			ok:   true,
+		},
+		{
+			name: "percentage only",
+			line: "Downloading 7%",
+			want: DownloadProgress{Percent: 7},
+			ok:   true,
+		},
+		{
+			name: "percentage over 100 is ignored",
+			line: "Speed 250% of last run",
+			want: DownloadProgress{Percent: -1},
+		},
+		{
+			name: "status line",
+			line: "Resolving qwen/qwen3-8b...",
+			want: DownloadProgress{Percent: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseDownloadProgress(tt.line)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ParseDownloadProgress(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestValidateDownloadKey(t *testing.T) {
+	tests := []struct {
+		model   string
+		wantErr bool
+	}{
+		{model: "qwen/qwen3-8b"},
+		{model: "lmstudio-community/Qwen3-8B-GGUF@q4_k_m"},
+		{model: "https://huggingface.co/Qwen/Qwen3-8B-GGUF"},
+		{model: "qwen3-8b", wantErr: true}, // A search term, lms would pick a match
+		{model: "qwen 3", wantErr: true},
+		{model: "-y/model", wantErr: true},
+		{model: "qwen/qwen3-8b/extra", wantErr: true},
+		{model: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateDownloadKey(tt.model); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateDownloadKey(%q) error = %v, want error %v", tt.model, err, tt.wantErr)
+		}
+	}
+}
+
+func TestStartDownloadRejectsSearchTerms(t *testing.T) {
+	c := newTestClient(t)
+	_, err := c.StartDownload(context.Background(), "qwen3")
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("StartDownload() error = %v, want a ValidationError", err)
+	}
+}
+
+func TestResolveDownload(t *testing.T) {
+	tests := []struct {
+		name    string
+		term    string
+		results string
+		status  int
+		want    string
+		wantErr bool
+	}{
+		{name: "model key", term: "qwen/qwen3-8b", want: "qwen/qwen3-8b"},
+		{name: "search term", term: " qwen3 8b ", results: `[{"id":"lmstudio-community/Qwen3-8B-GGUF","downloads":10}]`, want: "lmstudio-community/Qwen3-8B-GGUF"},
+		{name: "no match", term: "qwen3", results: `[]`, wantErr: true},
+		{name: "search failed", term: "qwen3", status: http.StatusTooManyRequests, wantErr: true},
+		{name: "empty", term: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query().Get("search")
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				io.WriteString(w, tt.results)
+			}))
+			defer server.Close()
+			defer func(url string) { modelSearchURL = url }(modelSearchURL)
+			modelSearchURL = server.URL
+
+			got, err := ResolveDownload(context.Background(), tt.term)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Fatalf("ResolveDownload(%q) = %q, %v, want %q, error %v", tt.term, got, err, tt.want, tt.wantErr)
+			}
+			// Model keys are never looked up
+			if tt.results != "" && query != "qwen3 8b" && query != "qwen3" {
+				t.Errorf("searched for %q", query)
+			}
+			if IsDownloadKey(tt.term) && query != "" {
+				t.Errorf("searched for the model key %q", tt.term)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Embedder is implemented by backends that can compute text embeddings
+type Embedder interface {
+	Embed(ctx context.Context, model string, inputs []string) ([][]float32, error)
+}
+
+// Embed computes embeddings through /v1/embeddings
+func (b *LMStudioBackend) Embed(ctx context.Context, model string, inputs []string) ([][]float32, error) {
+	resp, err := b.openAIClient.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input: inputs,
+		Model: openai.EmbeddingModel(model),
+	})
+	if err != nil {
+		var reqErr *openai.RequestError
+		if errors.As(err, &reqErr) {
+			return nil, &APIError{
+				StatusCode: reqErr.HTTPStatusCode,
+				Status:     reqErr.HTTPStatus,
+				Body:       string(reqErr.Body),
+			}
+		}
+		return nil, fmt.Errorf("create embeddings: %w", err)
+	}
+
+	if len(resp.Data) != len(inputs) {
+		return nil, fmt.Errorf("create embeddings: got %d embeddings for %d inputs", len(resp.Data), len(inputs))
+	}
+
+	// Data is not guaranteed to be in input order
+	vectors := make([][]float32, len(inputs))
+	for _, embedding := range resp.Data {
+		if embedding.Index < 0 || embedding.Index >= len(inputs) {
+			return nil, fmt.Errorf("create embeddings: unexpected index %d", embedding.Index)
+		}
+		vectors[embedding.Index] = embedding.Embedding
+	}
+	return vectors, nil
+}
+
+// Embed computes one embedding per input with the configured embedding model
+func (c *Client) Embed(ctx context.Context, inputs []string) ([][]float32, error) {
+	if c.IsClosed() {
+		return nil, fmt.Errorf("client is closed")
+	}
+
+	embedder, ok := c.backend.(Embedder)
+	if !ok {
+		return nil, fmt.Errorf("embeddings: %w", ErrNotSupported)
+	}
+
+	vectors := make([][]float32, 0, len(inputs))
+	for start := 0; start < len(inputs); start += EmbeddingBatchSize {
+		batch := inputs[start:min(start+EmbeddingBatchSize, len(inputs))]
+		batchVectors, err := embedder.Embed(ctx, c.config.EmbeddingModel, batch)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batchVectors...)
+	}
+	return vectors, nil
+}
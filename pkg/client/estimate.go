@@ -0,0 +1,114 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ModelEstimate is what lms predicts about loading a model
+type ModelEstimate struct {
+	Status        Status
+	GPUMemory     int64  // Estimated GPU memory in bytes, 0 when not reported
+	TotalMemory   int64  // Estimated total memory in bytes, 0 when not reported
+	ContextLength int    // Context length the estimate assumes, 0 when not reported
+	Verdict       string // The estimate sentence, such as "This model may be loaded ..."
+	Reason        string // Further explanation from the guardrails, if any
+}
+
+// memoryUnits maps the units lms prints to bytes
+var memoryUnits = map[string]float64{
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+}
+
+// ParseModelEstimate reads the output of lms load --estimate-only:
+//
+//	Model: qwen/qwen3-8b
+//	Context Length: 4,096
+//	Estimated GPU Memory:   5.20 GB
+//	Estimated Total Memory: 5.60 GB
+//
+//	Estimate: This model may be loaded based on your resource guardrails settings.
+//
+// Lines it does not know are kept as the reason when they follow the verdict.
+func ParseModelEstimate(output string) ModelEstimate {
+	estimate := ModelEstimate{Status: StatusUnavailable}
+	if strings.Contains(output, EstimateSuccessMessage) {
+		estimate.Status = StatusAvailable
+	}
+
+	var reason []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch {
+		case ok && key == "estimate":
+			estimate.Verdict = value
+		case ok && strings.Contains(key, "gpu memory"):
+			estimate.GPUMemory, _ = ParseMemorySize(value)
+		case ok && strings.Contains(key, "total memory"):
+			estimate.TotalMemory, _ = ParseMemorySize(value)
+		case ok && key == "context length":
+			estimate.ContextLength, _ = strconv.Atoi(strings.ReplaceAll(value, ",", ""))
+		case estimate.Verdict != "":
+			reason = append(reason, line)
+		}
+	}
+	estimate.Reason = strings.Join(reason, " ")
+	return estimate
+}
+
+// ParseMemorySize parses sizes such as "5.20 GB" or "512 MiB"
+func ParseMemorySize(size string) (int64, error) {
+	fields := strings.Fields(size)
+	if len(fields) == 1 {
+		// "5.2GB"
+		i := strings.IndexFunc(fields[0], func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if i > 0 {
+			fields = []string{fields[0][:i], fields[0][i:]}
+		}
+	}
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+
+	value, err := strconv.ParseFloat(strings.ReplaceAll(fields[0], ",", ""), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	unit, ok := memoryUnits[strings.ToUpper(fields[1])]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit in size %q", size)
+	}
+	return int64(value * unit), nil
+}
+
+// FormatBytes formats a size with binary units, 4.9 GiB
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size <= 0 {
+		return "-"
+	}
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
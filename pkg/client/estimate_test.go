@@ -0,0 +1,85 @@
+package client
+
+import "testing"
+
+func TestParseMemorySize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{size: "5.20 GB", want: 5.2e9},
+		{size: "512 MiB", want: 512 << 20},
+		{size: "5.2GB", want: 5.2e9},
+		{size: "1,024 KB", want: 1.024e6},
+		{size: "3 gib", want: 3 << 30},
+		{size: "10 B", want: 10},
+		{size: "", wantErr: true},
+		{size: "5.2", wantErr: true},
+		{size: "five GB", wantErr: true},
+		{size: "5 PB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMemorySize(tt.size)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMemorySize(%q) = %d, %v, want %d, error %v", tt.size, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseModelEstimate(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   ModelEstimate
+	}{
+		{
+			name: "fits",
+			output: `Model: qwen/qwen3-8b
+Context Length: 4,096
+Estimated GPU Memory:   5.20 GB
+Estimated Total Memory: 5.60 GB
+
+` + EstimateSuccessMessage + "\n",
+			want: ModelEstimate{
+				Status:        StatusAvailable,
+				GPUMemory:     5.2e9,
+				TotalMemory:   5.6e9,
+				ContextLength: 4096,
+				Verdict:       "This model may be loaded based on your resource guardrails settings.",
+			},
+		},
+		{
+			name: "does not fit, with a reason",
+			output: `Model: qwen/qwen3-32b
+Estimated GPU Memory: 21 GiB
+Estimated Total Memory: 22 GiB
+
+Estimate: This model will fail to load based on your resource guardrails settings.
+The model needs more memory than
+the guardrails allow.
+`,
+			want: ModelEstimate{
+				Status:      StatusUnavailable,
+				GPUMemory:   21 << 30,
+				TotalMemory: 22 << 30,
+				Verdict:     "This model will fail to load based on your resource guardrails settings.",
+				Reason:      "The model needs more memory than the guardrails allow.",
+			},
+		},
+		{
+			name:   "unknown output",
+			output: "Error: model not found",
+			want:   ModelEstimate{Status: StatusUnavailable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseModelEstimate(tt.output); got != tt.want {
+				t.Errorf("ParseModelEstimate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
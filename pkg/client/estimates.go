@@ -0,0 +1,156 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EstimateResult is the load estimate of one downloaded model
+type EstimateResult struct {
+	ModelKey string
+	Estimate ModelEstimate
+	Err      error // Set when the estimate could not be made
+}
+
+// estimateCache remembers estimates by model path for one set of loaded
+// models, since loaded models decide how much memory is left
+type estimateCache struct {
+	mu        sync.Mutex
+	loadedSet string
+	results   map[string]ModelEstimate
+}
+
+// lookup returns a cached estimate, dropping the cache when the loaded set changed
+func (e *estimateCache) lookup(loadedSet, identifier string) (ModelEstimate, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.loadedSet != loadedSet || e.results == nil {
+		e.loadedSet = loadedSet
+		e.results = make(map[string]ModelEstimate)
+		return ModelEstimate{}, false
+	}
+	estimate, ok := e.results[identifier]
+	return estimate, ok
+}
+
+// store caches an estimate made while loadedSet was loaded
+func (e *estimateCache) store(loadedSet, identifier string, estimate ModelEstimate) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.loadedSet == loadedSet && e.results != nil {
+		e.results[identifier] = estimate
+	}
+}
+
+// estimateIdentifier is the name lms estimates a model by. Models listed
+// over REST have no path, their key works for lms too.
+func estimateIdentifier(model LMSDownloadedListItem) string {
+	if model.Path != "" {
+		return model.Path
+	}
+	return model.ModelKey
+}
+
+// loadedSet describes the loaded models for the estimate cache. It is empty
+// when they cannot be listed, which disables caching.
+func (c *Client) loadedSet() string {
+	loaded, err := c.backend.GetLoadedModels()
+	if err != nil {
+		return ""
+	}
+	ids := extractLoadedIdentifiers(loaded)
+	sort.Strings(ids)
+	return "loaded:" + strings.Join(ids, ",")
+}
+
+func extractLoadedIdentifiers(models []LMSLoadedListItem) []string {
+	ids := make([]string, len(models))
+	for i, model := range models {
+		ids[i] = model.Identifier
+	}
+	return ids
+}
+
+// EstimateModels estimates whether each model fits, running at most
+// MaxConcurrentEstimates estimates at once. Every result is sent on the
+// returned channel as soon as it is known; the channel is closed once all
+// models are done or the client is cleaned up. Estimates are cached until
+// the set of loaded models changes, failures are not cached.
+func (c *Client) EstimateModels(models []LMSDownloadedListItem) <-chan EstimateResult {
+	// Buffered for every model so workers never wait on a slow reader
+	results := make(chan EstimateResult, len(models))
+	if c.IsClosed() {
+		close(results)
+		return results
+	}
+
+	loadedSet := c.loadedSet()
+	jobs := make(chan LMSDownloadedListItem, len(models))
+	for _, model := range models {
+		if loadedSet != "" {
+			if estimate, ok := c.estimates.lookup(loadedSet, estimateIdentifier(model)); ok {
+				results <- EstimateResult{ModelKey: model.ModelKey, Estimate: estimate}
+				continue
+			}
+		}
+		jobs <- model
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for range min(MaxConcurrentEstimates, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for model := range jobs {
+				if c.IsClosed() {
+					return
+				}
+				identifier := estimateIdentifier(model)
+				estimate, err := c.GetModelEstimate(identifier)
+				if err == nil && loadedSet != "" {
+					c.estimates.store(loadedSet, identifier, estimate)
+				}
+				results <- EstimateResult{ModelKey: model.ModelKey, Estimate: estimate, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// ApplyEstimate records an estimate on the model of models it belongs to
+func ApplyEstimate(models []LMSDownloadedListItem, result EstimateResult) {
+	for i := range models {
+		if models[i].ModelKey != result.ModelKey {
+			continue
+		}
+		models[i].CanLoad = result.Err == nil && result.Estimate.Status == StatusAvailable
+		models[i].EstimateErr = result.Err
+		if result.Err == nil {
+			estimate := result.Estimate
+			models[i].Estimate = &estimate
+		} else {
+			models[i].Estimate = nil
+		}
+	}
+}
+
+// EstimateFailure summarizes the failed estimates of a run, or returns nil
+// when none failed
+func EstimateFailure(failed int, first error) error {
+	switch {
+	case failed == 0:
+		return nil
+	case failed == 1:
+		return fmt.Errorf("load estimate failed: %w", first)
+	default:
+		return fmt.Errorf("load estimates failed for %d models, first error: %w", failed, first)
+	}
+}
@@ -1,11 +1,16 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os/exec"
+	"regexp"
 	"strings"
+	"time"
 )
 
 // Allowed LMS commands (allowlist)
@@ -15,11 +20,14 @@ var allowedCommands = map[string]bool{
 	"unload": true,
 	"ls":     true,
 	"ps":     true,
+	"get":    true,
 }
 
-func (c *Client) RunLMSCommand(command []string) (string, error) {
+// validateLMSCommand checks a command against the allowlist and makes sure
+// lms is installed
+func (b *LMStudioBackend) validateLMSCommand(command []string) error {
 	if len(command) == 0 {
-		return "", ValidationError{
+		return ValidationError{
 			Field:   "command",
 			Value:   "",
 			Message: "command cannot be empty",
@@ -28,7 +36,7 @@ func (c *Client) RunLMSCommand(command []string) (string, error) {
 
 	// Validate command against allowlist
 	if !allowedCommands[command[0]] {
-		return "", ValidationError{
+		return ValidationError{
 			Field:   "command",
 			Value:   command[0],
 			Message: fmt.Sprintf("command not allowed: %s", command[0]),
@@ -38,7 +46,7 @@ func (c *Client) RunLMSCommand(command []string) (string, error) {
 	// Validate all arguments don't contain dangerous characters
 	for i, arg := range command {
 		if strings.ContainsAny(arg, "&|;`$\x00") {
-			return "", ValidationError{
+			return ValidationError{
 				Field:   fmt.Sprintf("command[%d]", i),
 				Value:   arg,
 				Message: "argument contains dangerous characters",
@@ -46,12 +54,16 @@ func (c *Client) RunLMSCommand(command []string) (string, error) {
 		}
 	}
 
-	if c.IsClosed() {
-		return "", fmt.Errorf("client is closed")
-	}
 	if _, err := exec.LookPath("lms"); err != nil {
-		c.logger.Error("lms command not found in PATH: %v", err)
-		return "", fmt.Errorf("lms command not found: %w", err)
+		b.logger.Error("lms command not found in PATH: %v", err)
+		return fmt.Errorf("lms command not found: %w", err)
+	}
+	return nil
+}
+
+func (b *LMStudioBackend) RunLMSCommand(command []string) (string, error) {
+	if err := b.validateLMSCommand(command); err != nil {
+		return "", err
 	}
 
 	cmd := exec.Command("lms", command...)
@@ -75,9 +87,82 @@ func (c *Client) RunLMSCommand(command []string) (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
-func (c *Client) GetStatus() (Status, error) {
+// streamLMSCommand runs a long lms command, calling onLine for every line
+// of its output. Progress bars redraw with carriage returns, so those end a
+// line as well. Cancelling ctx kills the command.
+func (b *LMStudioBackend) streamLMSCommand(ctx context.Context, command []string, onLine func(string)) error {
+	if err := b.validateLMSCommand(command); err != nil {
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	cmd := exec.CommandContext(ctx, "lms", command...)
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	// Children of lms may keep the output open after it was killed
+	cmd.WaitDelay = time.Second
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("Failed to run lms command: %w", err)
+	}
+
+	// The last lines explain a failure
+	var tail []string
+	scanned := make(chan struct{})
+	go func() {
+		defer close(scanned)
+		scanner := bufio.NewScanner(reader)
+		scanner.Split(scanTerminalLines)
+		for scanner.Scan() {
+			line := strings.TrimSpace(ansiEscape.ReplaceAllString(scanner.Text(), ""))
+			if line == "" {
+				continue
+			}
+			tail = append(tail, line)
+			if len(tail) > 3 {
+				tail = tail[1:]
+			}
+			onLine(line)
+		}
+		// Keep draining so the command never blocks on a full pipe
+		io.Copy(io.Discard, reader)
+	}()
+
+	err := cmd.Wait()
+	writer.Close()
+	<-scanned
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		if len(tail) > 0 {
+			return fmt.Errorf("Failed to run lms command: %w, output: %s", err, strings.Join(tail, " "))
+		}
+		return fmt.Errorf("Failed to run lms command: %w", err)
+	}
+	return nil
+}
+
+// ansiEscape matches the colour and cursor sequences of terminal output
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// scanTerminalLines is a bufio.SplitFunc ending lines at \n or \r
+func scanTerminalLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func (b *LMStudioBackend) cliGetStatus() (Status, error) {
 	cmd := []string{"status"}
-	output, err := c.RunLMSCommand(cmd)
+	output, err := b.RunLMSCommand(cmd)
 
 	if err != nil {
 		return StatusUnknown, fmt.Errorf("Failed to run status %w", err)
@@ -89,21 +174,18 @@ func (c *Client) GetStatus() (Status, error) {
 	return StatusOff, nil
 }
 
-func (c *Client) GetModelEstimate(identifier string) (Status, error) {
+func (b *LMStudioBackend) cliGetModelEstimate(identifier string) (ModelEstimate, error) {
 	cmd := []string{"load", "--exact", "--estimate-only", identifier}
-	output, err := c.RunLMSCommand(cmd)
+	output, err := b.RunLMSCommand(cmd)
 	if err != nil {
-		return StatusUnknown, fmt.Errorf("Failed to run lms load --estimate-only for %s: %w", identifier, err)
-	}
-	if strings.Contains(output, EstimateSuccessMessage) {
-		return StatusAvailable, nil
+		return ModelEstimate{Status: StatusUnknown}, fmt.Errorf("Failed to run lms load --estimate-only for %s: %w", identifier, err)
 	}
-	return StatusUnavailable, nil
+	return ParseModelEstimate(output), nil
 }
 
-func (c *Client) GetDownloadedModelsWithoutEstimates() ([]LMSDownloadedListItem, error) {
+func (b *LMStudioBackend) cliGetDownloadedModels() ([]LMSDownloadedListItem, error) {
 	cmd := []string{"ls", "--json"}
-	output, err := c.RunLMSCommand(cmd)
+	output, err := b.RunLMSCommand(cmd)
 
 	if err != nil {
 		return []LMSDownloadedListItem{}, fmt.Errorf("Failed to run lms ls: %w", err)
@@ -128,46 +210,9 @@ func (c *Client) GetDownloadedModelsWithoutEstimates() ([]LMSDownloadedListItem,
 	return filteredList, nil
 }
 
-func (c *Client) GetDownloadedModels() ([]LMSDownloadedListItem, error) {
-	if c.IsClosed() {
-		return nil, fmt.Errorf("client is closed")
-	}
-	cmd := []string{"ls", "--json"}
-	output, err := c.RunLMSCommand(cmd)
-
-	if err != nil {
-		return []LMSDownloadedListItem{}, fmt.Errorf("Failed to run lms ls: %w", err)
-	}
-
-	var downloadedList []LMSDownloadedListItem
-	if err := json.Unmarshal([]byte(output), &downloadedList); err != nil {
-		return []LMSDownloadedListItem{}, fmt.Errorf("Failed to parse JSON output: %w", err)
-	}
-
-	var filteredList []LMSDownloadedListItem
-	for _, item := range downloadedList {
-		if item.ModelKey != DefaultEmbeddingModelKey {
-			filteredList = append(filteredList, item)
-		}
-	}
-	downloadedList = filteredList
-
-	for i, element := range downloadedList {
-		status, err := c.GetModelEstimate(element.Path)
-		if err != nil {
-			return []LMSDownloadedListItem{}, fmt.Errorf("Failed to run estimation is listing: %w", err)
-		}
-		downloadedList[i].CanLoad = (status == StatusAvailable)
-	}
-	return downloadedList, nil
-}
-
-func (c *Client) GetLoadedModels() ([]LMSLoadedListItem, error) {
-	if c.IsClosed() {
-		return nil, fmt.Errorf("client is closed")
-	}
+func (b *LMStudioBackend) cliGetLoadedModels() ([]LMSLoadedListItem, error) {
 	cmd := []string{"ps", "--json"}
-	output, err := c.RunLMSCommand(cmd)
+	output, err := b.RunLMSCommand(cmd)
 
 	if err != nil {
 		return []LMSLoadedListItem{}, fmt.Errorf("Failed to run lms ls: %w", err)
@@ -181,69 +226,61 @@ func (c *Client) GetLoadedModels() ([]LMSLoadedListItem, error) {
 	return downloadedList, nil
 }
 
-func (c *Client) LoadModel(modelID string) error {
-	if err := ValidateModelID(modelID); err != nil {
-		return fmt.Errorf("invalid model ID: %w", err)
-	}
-
-	if c.IsClosed() {
-		return fmt.Errorf("client is closed")
-	}
-
-	_, err := c.RunLMSCommand([]string{"load", modelID})
-	if err != nil {
-		c.logger.Error("Failed to load model %s: %v", modelID, err)
-		return fmt.Errorf("failed to load model: %w", err)
-	}
-	return nil
+func (b *LMStudioBackend) cliLoadModel(modelID string, options LoadOptions) error {
+	_, err := b.RunLMSCommand(append([]string{"load", modelID}, options.lmsArgs()...))
+	return err
 }
 
-func (c *Client) UnloadModel(modelKey string) error {
-	if err := ValidateModelID(modelKey); err != nil {
-		return fmt.Errorf("invalid model key: %w", err)
-	}
-
-	if c.IsClosed() {
-		return fmt.Errorf("client is closed")
-	}
-
-	_, err := c.RunLMSCommand([]string{"unload", modelKey})
-	if err != nil {
-		c.logger.Error("Failed to unload model %s: %v", modelKey, err)
-		return fmt.Errorf("failed to unload model: %w", err)
-	}
-	c.logger.Info("Successfully unloaded model: %s", modelKey)
-	return nil
+func (b *LMStudioBackend) cliUnloadModel(modelKey string) error {
+	_, err := b.RunLMSCommand([]string{"unload", modelKey})
+	return err
 }
 
-func (c *Client) UnloadAllModels() error {
-	if c.IsClosed() {
-		return fmt.Errorf("client is closed")
-	}
-	_, err := c.RunLMSCommand([]string{"unload", "--all"})
+func (b *LMStudioBackend) cliUnloadAllModels() error {
+	_, err := b.RunLMSCommand([]string{"unload", "--all"})
 	if err == nil {
-		c.logger.Info("Unloaded all models using --all flag")
+		b.logger.Info("Unloaded all models using --all flag")
 		return nil
 	}
 
-	loadedModels, err := c.GetLoadedModels()
+	loadedModels, err := b.cliGetLoadedModels()
 	if err != nil {
-		c.logger.Error("Failed to get loaded models for unload all: %v", err)
+		b.logger.Error("Failed to get loaded models for unload all: %v", err)
 		return err
 	}
 
 	if len(loadedModels) == 0 {
-		c.logger.Info("No models to unload")
+		b.logger.Info("No models to unload")
 		return nil
 	}
 
 	for _, model := range loadedModels {
-		if err := c.UnloadModel(model.Identifier); err != nil {
-			c.logger.Error("Failed to unload model %s during unload all: %v", model.Identifier, err)
+		if err := b.cliUnloadModel(model.Identifier); err != nil {
+			b.logger.Error("Failed to unload model %s during unload all: %v", model.Identifier, err)
 			return err
 		}
 	}
 
-	c.logger.Info("Unloaded all models individually")
+	b.logger.Info("Unloaded all models individually")
 	return nil
 }
+
+func (b *LMStudioBackend) cliDownloadModel(ctx context.Context, model string, progress func(DownloadProgress)) error {
+	// --yes skips the confirmation, the key was checked to name one model
+	command := []string{"get", "--yes", model}
+
+	current := DownloadProgress{Percent: -1}
+	err := b.streamLMSCommand(ctx, command, func(line string) {
+		if parsed, ok := ParseDownloadProgress(line); ok {
+			parsed.Status = current.Status
+			current = parsed
+		} else {
+			current.Status = line
+		}
+		progress(current)
+	})
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("Failed to run lms get for %s: %w", model, err)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Forwarder is implemented by backends that can pass OpenAI-compatible
+// requests through to the server unchanged
+type Forwarder interface {
+	Forward(ctx context.Context, method, path string, body io.Reader, header http.Header) (*http.Response, error)
+}
+
+// forwardedHeaders are the request headers passed on to the server
+var forwardedHeaders = []string{"Content-Type", "Accept", "Cache-Control"}
+
+// Forward sends a request to path on the LM Studio server. The caller owns
+// the response body.
+func (b *LMStudioBackend) Forward(ctx context.Context, method, path string, body io.Reader, header http.Header) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, method, b.config.GetFullURL()+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	for _, name := range forwardedHeaders {
+		if value := header.Get(name); value != "" {
+			httpReq.Header.Set(name, value)
+		}
+	}
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	return resp, nil
+}
+
+// Forward passes a raw request through to the server, as the serve proxy
+// does. Non-2xx responses are returned as they are, not as errors.
+func (c *Client) Forward(ctx context.Context, method, path string, body io.Reader, header http.Header) (*http.Response, error) {
+	if c.IsClosed() {
+		return nil, fmt.Errorf("client is closed")
+	}
+
+	forwarder, ok := c.backend.(Forwarder)
+	if !ok {
+		return nil, fmt.Errorf("forwarding requests: %w", ErrNotSupported)
+	}
+	return forwarder.Forward(ctx, method, path, body, header)
+}
@@ -5,30 +5,231 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// SendResponseStream streams a response for req. When the model calls
+// registered tools, they are executed and their results are sent back in
+// follow-up requests until the model answers without calling any tool.
+//
+// Requests with Store set are chained on the server: follow-ups carry only
+// the new input items and the ID of the previous response. If the server no
+// longer knows that response, the request is resent with the full history.
 func (c *Client) SendResponseStream(ctx context.Context, req ResponseRequest, callback func(string, string)) error {
-	c.cancelled.Store(false)
-	return c.sendResponseStreamWithRetry(ctx, req, callback, 3)
+	ctx, cancel := c.beginRequest(ctx)
+	defer cancel()
+	return c.streamResponse(ctx, req, callback)
 }
 
-func (c *Client) sendResponseStreamWithRetry(ctx context.Context, req ResponseRequest, callback func(string, string), maxRetries int) error {
-	req.Stream = true
+// beginRequest starts a request that CancelRequest can stop. Everything the
+// request sends, compaction included, must use the returned context.
+func (c *Client) beginRequest(ctx context.Context) (context.Context, context.CancelFunc) {
+	c.cancelled.Store(false)
+
+	ctx, cancel := context.WithCancel(ctx)
 
 	c.mu.Lock()
-	if c.lastResponseID != nil {
-		req.PreviousResponseID = c.lastResponseID
-	}
+	c.requestCancel = cancel
+	c.lastMetrics = StreamMetrics{}
+	c.lastValidation = nil
 	c.mu.Unlock()
 
+	return ctx, cancel
+}
+
+// streamResponse is SendResponseStream within a request that has begun
+func (c *Client) streamResponse(ctx context.Context, req ResponseRequest, callback func(string, string)) error {
+	if c.tools.Len() > 0 {
+		req.Tools = c.tools.Tools()
+	}
+
+	for round := 0; ; round++ {
+		result, err := c.backend.StreamChat(ctx, req, callback)
+		if err != nil && req.PreviousResponseID != nil && req.history != nil && isUnknownResponseError(err) {
+			c.logger.Warn("Server rejected previous response %s, resending the full history", *req.PreviousResponseID)
+			req = withFullHistory(req)
+			result, err = c.backend.StreamChat(ctx, req, callback)
+		}
+		if err != nil {
+			// The server may not have stored this turn, so the next
+			// request has to start a new chain
+			c.ClearResponseHistory()
+			if c.isCancelled() {
+				return fmt.Errorf("request cancelled by user")
+			}
+			return err
+		}
+
+		c.mu.Lock()
+		if req.Store && result.ResponseID != "" {
+			c.lastResponseID = &result.ResponseID
+		} else {
+			c.lastResponseID = nil
+		}
+		c.lastMetrics.add(result.Metrics)
+		c.mu.Unlock()
+
+		if len(result.ToolCalls) == 0 {
+			return nil
+		}
+
+		if round >= MaxToolCallRounds {
+			return fmt.Errorf("model kept calling tools after %d rounds", MaxToolCallRounds)
+		}
+
+		items := c.runToolCalls(ctx, result.ToolCalls, callback)
+		if req.Store && result.ResponseID != "" {
+			// The stored response already holds the function calls
+			req.history = append(fullInput(req), items...)
+			req.Input = functionCallOutputs(items)
+			req.PreviousResponseID = &result.ResponseID
+		} else {
+			req = withFullHistory(req)
+			req.Input = append(req.Input, items...)
+		}
+	}
+}
+
+// truncateDisplay shortens text to at most limit runes for display, marking
+// the cut with an ellipsis. It never splits a multi-byte rune.
+func truncateDisplay(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return string([]rune(text)[:limit]) + "…"
+}
+
+// fullInput returns the complete input behind a possibly chained request
+func fullInput(req ResponseRequest) []InputMessage {
+	if req.PreviousResponseID != nil && req.history != nil {
+		return req.history
+	}
+	return req.Input
+}
+
+// functionCallOutputs keeps only the function call outputs of items
+func functionCallOutputs(items []InputMessage) []InputMessage {
+	var outputs []InputMessage
+	for _, item := range items {
+		if item.Type == InputTypeFunctionCallOutput {
+			outputs = append(outputs, item)
+		}
+	}
+	return outputs
+}
+
+// runToolCalls executes the calls and returns the input items that report
+// them back to the model
+func (c *Client) runToolCalls(ctx context.Context, calls []ToolCall, callback func(string, string)) []InputMessage {
+	var items []InputMessage
+	for _, call := range calls {
+		callback(fmt.Sprintf("→ %s(%s)\n", call.Name, call.Arguments), ContentTypeTool)
+
+		output, err := c.tools.Execute(ctx, call.Name, call.Arguments)
+		if err != nil {
+			c.logger.Warn("Tool %s failed: %v", call.Name, err)
+			output = fmt.Sprintf("error: %v", err)
+		} else {
+			c.logger.Info("Tool %s returned %d chars", call.Name, len(output))
+		}
+
+		callback(fmt.Sprintf("← %s\n", truncateDisplay(output, MaxToolOutputDisplayLength)), ContentTypeTool)
+
+		items = append(items,
+			InputMessage{
+				Type:      InputTypeFunctionCall,
+				CallID:    call.CallID,
+				Name:      call.Name,
+				Arguments: call.Arguments,
+			},
+			InputMessage{
+				Type:   InputTypeFunctionCallOutput,
+				CallID: call.CallID,
+				Output: output,
+			},
+		)
+	}
+	return items
+}
+
+// APIError is returned when the server answers with a non-200 status
+type APIError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error: %s", e.Status)
+}
+
+// isUnknownResponseError reports whether the server rejected a request
+// because it does not know its previous_response_id
+func isUnknownResponseError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.StatusCode != HTTPStatusBadRequest && apiErr.StatusCode != HTTPStatusNotFound {
+		return false
+	}
+	body := strings.ToLower(apiErr.Body)
+	return strings.Contains(body, "previous_response") ||
+		(strings.Contains(body, "response") && strings.Contains(body, "not found"))
+}
+
+// newAPIError captures the status and the start of the body of a failed response
+func newAPIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxErrorBodyLength))
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       strings.TrimSpace(string(body)),
+	}
+}
+
+// newHTTPClient builds the HTTP client shared by every backend request
+func newHTTPClient(config ClientConfig) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(config.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{
+		TLSClientConfig:     tlsConfig,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+	}
+
+	return &http.Client{
+		Timeout: config.HTTPTimeout,
+		Transport: &authTransport{
+			base:    transport,
+			apiKey:  config.ResolveAPIKey(),
+			headers: config.Headers,
+		},
+	}, nil
+}
+
+func (b *LMStudioBackend) sendResponseStreamWithRetry(ctx context.Context, req ResponseRequest, callback func(string, string), maxRetries int) (StreamResult, error) {
+	req.Stream = true
+
 	jsonData, err := json.Marshal(req)
 	if err != nil {
-		return fmt.Errorf("marshal request: %w", err)
+		return StreamResult{}, fmt.Errorf("marshal request: %w", err)
 	}
 
 	var lastErr error
@@ -36,56 +237,59 @@ func (c *Client) sendResponseStreamWithRetry(ctx context.Context, req ResponseRe
 		if attempt > 0 {
 			backoff := DefaultRetryBaseDelay * time.Duration(1<<uint(attempt-1))
 			time.Sleep(backoff)
-			c.logger.Info("Retrying streaming request (attempt %d/%d) after %v", attempt+1, maxRetries+1, backoff)
+			b.logger.Info("Retrying streaming request (attempt %d/%d) after %v", attempt+1, maxRetries+1, backoff)
 		}
 
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", c.config.GetFullURL()+"/v1/responses", bytes.NewBuffer(jsonData))
+		timer := newStreamTimer()
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", b.config.GetFullURL()+"/v1/responses", bytes.NewBuffer(jsonData))
 		if err != nil {
-			return fmt.Errorf("create request: %w", err)
+			return StreamResult{}, fmt.Errorf("create request: %w", err)
 		}
 		httpReq.Header.Set("Content-Type", "application/json")
 		httpReq.Header.Set("Accept", "text/event-stream")
 		httpReq.Header.Set("Cache-Control", "no-cache")
 
-		resp, err := c.httpClient.Do(httpReq)
+		resp, err := b.httpClient.Do(httpReq)
 		if err != nil {
 			lastErr = fmt.Errorf("http request: %w", err)
 			if attempt < maxRetries {
 				continue
 			}
-			return lastErr
+			return StreamResult{}, lastErr
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != HTTPStatusOK {
+			apiErr := newAPIError(resp)
 			// Don't retry on client errors (4xx), but retry on server errors (5xx)
 			if resp.StatusCode >= HTTPStatusInternalError && attempt < maxRetries {
-				lastErr = fmt.Errorf("api error: %s", resp.Status)
+				lastErr = apiErr
 				continue
 			}
-			return fmt.Errorf("api error: %s", resp.Status)
+			return StreamResult{}, apiErr
 		}
 
-		err = c.parseSSEStream(resp.Body, callback)
+		result, err := b.parseSSEStream(ctx, resp.Body, timer.wrap(callback))
 		if err != nil {
-			return fmt.Errorf("parse SSE stream: %w", err)
+			return StreamResult{}, fmt.Errorf("parse SSE stream: %w", err)
 		}
 
-		return nil
+		result.Metrics = timer.metrics(result.Metrics.Usage)
+		return result, nil
 	}
 
-	return lastErr
+	return StreamResult{}, lastErr
 }
 
-func (c *Client) parseSSEStream(body io.Reader, callback func(string, string)) error {
+func (b *LMStudioBackend) parseSSEStream(ctx context.Context, body io.Reader, callback func(string, string)) (StreamResult, error) {
 	scanner := bufio.NewScanner(body)
 	var eventData strings.Builder
 	var eventType string
-	var responseID string
+	state := &sseStreamState{}
 
 	for scanner.Scan() {
-		if c.isCancelled() {
-			return fmt.Errorf("request cancelled by user")
+		if ctx.Err() != nil {
+			return StreamResult{}, fmt.Errorf("request cancelled by user")
 		}
 
 		line := scanner.Text()
@@ -95,8 +299,8 @@ func (c *Client) parseSSEStream(body io.Reader, callback func(string, string)) e
 				eventStr := eventData.String()
 				eventData.Reset()
 
-				if err := c.processSSEEvent(eventType, eventStr, &responseID, callback); err != nil {
-					return fmt.Errorf("process SSE event: %w", err)
+				if err := b.processSSEEvent(eventType, eventStr, state, callback); err != nil {
+					return StreamResult{}, fmt.Errorf("process SSE event: %w", err)
 				}
 				eventType = ""
 			}
@@ -115,19 +319,40 @@ func (c *Client) parseSSEStream(body io.Reader, callback func(string, string)) e
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("scanner error: %w", err)
+		return StreamResult{}, fmt.Errorf("scanner error: %w", err)
 	}
 
-	if responseID != "" {
-		c.mu.Lock()
-		c.lastResponseID = &responseID
-		c.mu.Unlock()
+	return state.result(), nil
+}
+
+// sseStreamState accumulates what a stream reports besides content
+type sseStreamState struct {
+	responseID string
+	toolCalls  []*ToolCall
+	usage      Usage
+}
+
+// toolCall returns the function call with the given output item ID
+func (s *sseStreamState) toolCall(itemID string) *ToolCall {
+	for _, call := range s.toolCalls {
+		if call.ItemID == itemID {
+			return call
+		}
 	}
+	call := &ToolCall{ItemID: itemID}
+	s.toolCalls = append(s.toolCalls, call)
+	return call
+}
 
-	return nil
+func (s *sseStreamState) result() StreamResult {
+	result := StreamResult{ResponseID: s.responseID, Metrics: StreamMetrics{Usage: s.usage}}
+	for _, call := range s.toolCalls {
+		result.ToolCalls = append(result.ToolCalls, *call)
+	}
+	return result
 }
 
-func (c *Client) processSSEEvent(eventType string, eventData string, responseID *string, callback func(string, string)) error {
+func (b *LMStudioBackend) processSSEEvent(eventType string, eventData string, state *sseStreamState, callback func(string, string)) error {
 	var data map[string]interface{}
 	if err := json.Unmarshal([]byte(eventData), &data); err != nil {
 		return fmt.Errorf("unmarshal event data: %w", err)
@@ -136,18 +361,58 @@ func (c *Client) processSSEEvent(eventType string, eventData string, responseID
 	switch eventType {
 	case "response.created":
 		if id, ok := data["response_id"].(string); ok {
-			*responseID = id
+			state.responseID = id
 		}
 	case "response.output_text.delta":
 		if delta, ok := data["delta"].(string); ok {
-			callback(delta, "output")
+			callback(delta, ContentTypeOutput)
 		}
 	case "response.reasoning_text.delta":
 		if delta, ok := data["delta"].(string); ok {
-			callback(delta, "reasoning")
+			callback(delta, ContentTypeReasoning)
+		}
+	case "response.output_item.added", "response.output_item.done":
+		var event ResponseOutputItemEvent
+		if err := json.Unmarshal([]byte(eventData), &event); err != nil {
+			return fmt.Errorf("unmarshal output item: %w", err)
+		}
+		if event.Item.Type != InputTypeFunctionCall {
+			break
+		}
+		call := state.toolCall(event.Item.ID)
+		call.CallID = event.Item.CallID
+		call.Name = event.Item.Name
+		if event.Item.Arguments != "" {
+			call.Arguments = event.Item.Arguments
+		}
+	case "response.function_call_arguments.delta":
+		var event ResponseFunctionCallArgumentsEvent
+		if err := json.Unmarshal([]byte(eventData), &event); err != nil {
+			return fmt.Errorf("unmarshal function call arguments: %w", err)
+		}
+		state.toolCall(event.ItemID).Arguments += event.Delta
+	case "response.function_call_arguments.done":
+		var event ResponseFunctionCallArgumentsEvent
+		if err := json.Unmarshal([]byte(eventData), &event); err != nil {
+			return fmt.Errorf("unmarshal function call arguments: %w", err)
+		}
+		if event.Arguments != "" {
+			state.toolCall(event.ItemID).Arguments = event.Arguments
 		}
 	case "response.completed":
-		// Streaming complete, no action needed
+		var event ResponseCompletedEvent
+		if err := json.Unmarshal([]byte(eventData), &event); err != nil {
+			return fmt.Errorf("unmarshal completed response: %w", err)
+		}
+		if state.responseID == "" && event.Response.ID != "" {
+			state.responseID = event.Response.ID
+		}
+		usage := event.Response.Usage
+		state.usage = Usage{
+			InputTokens:     usage.InputTokens,
+			OutputTokens:    usage.OutputTokens,
+			ReasoningTokens: usage.OutputTokensDetails.ReasoningTokens,
+		}
 	}
 
 	return nil
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseSSEStream(t *testing.T) {
+	tests := []struct {
+		name      string
+		stream    string
+		want      StreamResult
+		output    string
+		reasoning string
+	}{
+		{
+			name: "text and reasoning",
+			stream: `event: response.created
+data: {"response_id":"resp_1"}
+
+event: response.reasoning_text.delta
+data: {"delta":"thinking"}
+
+event: response.output_text.delta
+data: {"delta":"Hello"}
+
+event: response.output_text.delta
+data: {"delta":", world"}
+
+event: response.completed
+data: {"response":{"id":"resp_1","usage":{"input_tokens":12,"output_tokens":7,"output_tokens_details":{"reasoning_tokens":3}}}}
+
+`,
+			want: StreamResult{
+				ResponseID: "resp_1",
+				Metrics:    StreamMetrics{Usage: Usage{InputTokens: 12, OutputTokens: 7, ReasoningTokens: 3}},
+			},
+			output:    "Hello, world",
+			reasoning: "thinking",
+		},
+		{
+			name: "streamed function call arguments",
+			stream: `event: response.output_item.added
+data: {"item":{"type":"function_call","id":"fc_1","call_id":"call_1","name":"read_file"}}
+
+event: response.function_call_arguments.delta
+data: {"item_id":"fc_1","delta":"{\"path\":"}
+
+event: response.function_call_arguments.delta
+data: {"item_id":"fc_1","delta":"\"go.mod\"}"}
+
+event: response.output_item.done
+data: {"item":{"type":"function_call","id":"fc_1","call_id":"call_1","name":"read_file"}}
+
+event: response.completed
+data: {"response":{"id":"resp_2","usage":{}}}
+
+`,
+			want: StreamResult{
+				ResponseID: "resp_2",
+				ToolCalls:  []ToolCall{{ItemID: "fc_1", CallID: "call_1", Name: "read_file", Arguments: `{"path":"go.mod"}`}},
+			},
+		},
+		{
+			name: "arguments done replaces the deltas",
+			stream: `event: response.function_call_arguments.delta
+data: {"item_id":"fc_1","delta":"{\"pa"}
+
+event: response.function_call_arguments.done
+data: {"item_id":"fc_1","arguments":"{\"path\":\"a\"}"}
+
+event: response.output_item.done
+data: {"item":{"type":"function_call","id":"fc_1","call_id":"call_1","name":"read_file"}}
+
+`,
+			want: StreamResult{
+				ToolCalls: []ToolCall{{ItemID: "fc_1", CallID: "call_1", Name: "read_file", Arguments: `{"path":"a"}`}},
+			},
+		},
+		{
+			name: "two calls keep their order",
+			stream: `event: response.output_item.done
+data: {"item":{"type":"function_call","id":"fc_1","call_id":"call_1","name":"first","arguments":"{}"}}
+
+event: response.output_item.done
+data: {"item":{"type":"function_call","id":"fc_2","call_id":"call_2","name":"second","arguments":"[]"}}
+
+event: response.output_item.done
+data: {"item":{"type":"message","id":"msg_1"}}
+
+`,
+			want: StreamResult{
+				ToolCalls: []ToolCall{
+					{ItemID: "fc_1", CallID: "call_1", Name: "first", Arguments: "{}"},
+					{ItemID: "fc_2", CallID: "call_2", Name: "second", Arguments: "[]"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output, reasoning strings.Builder
+			callback := func(content, contentType string) {
+				switch contentType {
+				case ContentTypeOutput:
+					output.WriteString(content)
+				case ContentTypeReasoning:
+					reasoning.WriteString(content)
+				}
+			}
+
+			backend := &LMStudioBackend{}
+			got, err := backend.parseSSEStream(context.Background(), strings.NewReader(tt.stream), callback)
+			if err != nil {
+				t.Fatalf("parseSSEStream() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSSEStream() = %+v, want %+v", got, tt.want)
+			}
+			if output.String() != tt.output {
+				t.Errorf("output = %q, want %q", output.String(), tt.output)
+			}
+			if reasoning.String() != tt.reasoning {
+				t.Errorf("reasoning = %q, want %q", reasoning.String(), tt.reasoning)
+			}
+		})
+	}
+}
+
+func TestParseSSEStreamInvalidJSON(t *testing.T) {
+	backend := &LMStudioBackend{}
+	stream := "event: response.output_text.delta\ndata: {not json\n\n"
+	if _, err := backend.parseSSEStream(context.Background(), strings.NewReader(stream), func(string, string) {}); err == nil {
+		t.Error("parseSSEStream() error = nil, want an error for invalid event data")
+	}
+}
+
+func TestTruncateDisplay(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{name: "short", text: "hello", limit: 10, want: "hello"},
+		{name: "exact", text: "hello", limit: 5, want: "hello"},
+		{name: "ascii", text: "hello world", limit: 5, want: "hello…"},
+		{name: "multi-byte runes", text: "héllo wörld", limit: 4, want: "héll…"},
+		{name: "cut after a wide rune", text: "日本語のテキスト", limit: 3, want: "日本語…"},
+		{name: "emoji", text: "👍👍👍", limit: 1, want: "👍…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateDisplay(tt.text, tt.limit)
+			if got != tt.want {
+				t.Errorf("truncateDisplay(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateDisplay(%q, %d) = %q is not valid UTF-8", tt.text, tt.limit, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const IndexFileName = "index.json"
+
+// Directories never worth indexing
+var skippedIndexDirs = []string{".git", ".hg", ".svn", "node_modules", "vendor", "dist", "build", "target"}
+
+// IndexChunk is a span of lines from an indexed file
+type IndexChunk struct {
+	Source    string    `json:"source"`
+	StartLine int       `json:"start_line"`
+	EndLine   int       `json:"end_line"`
+	Text      string    `json:"text"`
+	Vector    []float32 `json:"vector"`
+}
+
+// Citation returns the location of the chunk, e.g. "pkg/client/http.go:40-80".
+// Sources under the working directory are shown relative to it.
+func (c IndexChunk) Citation() string {
+	source := c.Source
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, source); err == nil && !strings.HasPrefix(rel, "..") {
+			source = rel
+		}
+	}
+	return fmt.Sprintf("%s:%d-%d", source, c.StartLine, c.EndLine)
+}
+
+// SearchResult is a chunk with its similarity to the query
+type SearchResult struct {
+	IndexChunk
+	Score float64
+}
+
+// VectorIndex is a small on-disk index of embedded file chunks
+type VectorIndex struct {
+	// Model is the embedding model that produced the vectors
+	Model  string       `json:"model"`
+	Chunks []IndexChunk `json:"chunks"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// LoadVectorIndex reads the index from the config directory, returning an
+// empty index when it does not exist yet
+func LoadVectorIndex() (*VectorIndex, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return &VectorIndex{}, err
+	}
+
+	index := &VectorIndex{path: filepath.Join(dir, IndexFileName)}
+
+	data, err := os.ReadFile(index.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return index, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	if err := json.Unmarshal(data, index); err != nil {
+		return index, fmt.Errorf("failed to parse index %s: %w", index.path, err)
+	}
+	return index, nil
+}
+
+// Save writes the index back to disk
+func (i *VectorIndex) Save() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.path == "" {
+		return fmt.Errorf("index has no file path")
+	}
+
+	data, err := json.Marshal(i)
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(i.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	tmpPath := i.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Rename(tmpPath, i.path); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of indexed chunks
+func (i *VectorIndex) Len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return len(i.Chunks)
+}
+
+// EmbeddingModel returns the model that produced the vectors
+func (i *VectorIndex) EmbeddingModel() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.Model
+}
+
+// replace swaps the chunks of every file under root for chunks. Vectors from
+// a different embedding model cannot be compared, so a model change drops
+// the whole index.
+func (i *VectorIndex) replace(model, root string, chunks []IndexChunk) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.Model != model {
+		i.Chunks = nil
+		i.Model = model
+	}
+
+	prefix := root + string(filepath.Separator)
+	i.Chunks = slices.DeleteFunc(i.Chunks, func(chunk IndexChunk) bool {
+		return chunk.Source == root || strings.HasPrefix(chunk.Source, prefix)
+	})
+	i.Chunks = append(i.Chunks, chunks...)
+}
+
+// Search returns the k chunks most similar to query
+func (i *VectorIndex) Search(query []float32, k int) []SearchResult {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	results := make([]SearchResult, 0, len(i.Chunks))
+	for _, chunk := range i.Chunks {
+		results = append(results, SearchResult{IndexChunk: chunk, Score: cosineSimilarity(query, chunk.Vector)})
+	}
+	sort.Slice(results, func(a, b int) bool { return results[a].Score > results[b].Score })
+
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// chunkFile splits a text file into overlapping spans of lines. Binary and
+// oversized files yield no chunks.
+func chunkFile(path string) ([]IndexChunk, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 || info.Size() > MaxIndexFileSize {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !isText(data) {
+		return nil, nil
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxIndexFileSize)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var chunks []IndexChunk
+	step := IndexChunkLines - IndexChunkOverlap
+	for start := 0; start < len(lines); start += step {
+		end := min(start+IndexChunkLines, len(lines))
+		text := strings.TrimSpace(strings.Join(lines[start:end], "\n"))
+		if text != "" {
+			chunks = append(chunks, IndexChunk{
+				Source:    path,
+				StartLine: start + 1,
+				EndLine:   end,
+				Text:      text,
+			})
+		}
+		if end == len(lines) {
+			break
+		}
+	}
+	return chunks, nil
+}
+
+func isText(data []byte) bool {
+	sample := data[:min(len(data), 512)]
+	if slices.Contains(sample, 0) {
+		return false
+	}
+	contentType := http.DetectContentType(sample)
+	return strings.HasPrefix(contentType, "text/") || strings.Contains(contentType, "json") || strings.Contains(contentType, "xml")
+}
+
+// IndexDirectory chunks and embeds every text file under dir, replacing
+// anything indexed from it before. It returns the number of chunks indexed.
+func (c *Client) IndexDirectory(ctx context.Context, dir string) (int, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return 0, fmt.Errorf("invalid directory: %w", err)
+	}
+
+	var chunks []IndexChunk
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if entry.IsDir() {
+			if path != root && (strings.HasPrefix(entry.Name(), ".") || slices.Contains(skippedIndexDirs, entry.Name())) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		fileChunks, err := chunkFile(path)
+		if err != nil {
+			c.logger.Warn("Skipping %s: %v", path, err)
+			return nil
+		}
+		chunks = append(chunks, fileChunks...)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+	if len(chunks) == 0 {
+		return 0, fmt.Errorf("no text files found in %s", root)
+	}
+	if len(chunks) > MaxIndexChunks {
+		return 0, fmt.Errorf("%s has %d chunks, more than the limit of %d", root, len(chunks), MaxIndexChunks)
+	}
+
+	c.logger.Info("Embedding %d chunks from %s with %s", len(chunks), root, c.config.EmbeddingModel)
+
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+	}
+	vectors, err := c.Embed(ctx, texts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to embed %s: %w", root, err)
+	}
+	for i := range chunks {
+		chunks[i].Vector = vectors[i]
+	}
+
+	index, err := c.vectorIndex()
+	if err != nil {
+		// An unreadable index is replaced rather than blocking indexing
+		c.logger.Warn("Starting a new index: %v", err)
+		index = &VectorIndex{path: index.path}
+	}
+	index.replace(c.config.EmbeddingModel, root, chunks)
+	if err := index.Save(); err != nil {
+		return 0, err
+	}
+	c.setVectorIndex(index)
+
+	c.logger.Info("Indexed %d chunks from %s", len(chunks), root)
+	return len(chunks), nil
+}
+
+// vectorIndex loads the index on first use. A failed load is remembered
+// until IndexDirectory replaces the index.
+func (c *Client) vectorIndex() (*VectorIndex, error) {
+	c.indexMu.Lock()
+	defer c.indexMu.Unlock()
+	if c.index == nil {
+		c.index, c.indexErr = LoadVectorIndex()
+	}
+	return c.index, c.indexErr
+}
+
+// setVectorIndex installs an index that was saved successfully
+func (c *Client) setVectorIndex(index *VectorIndex) {
+	c.indexMu.Lock()
+	defer c.indexMu.Unlock()
+	c.index, c.indexErr = index, nil
+}
+
+// RetrievalEnabled reports whether relevant indexed chunks are added to prompts
+func (c *Client) RetrievalEnabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.retrieval
+}
+
+// SetRetrieval turns retrieval over the vector index on or off
+func (c *Client) SetRetrieval(enabled bool) {
+	c.mu.Lock()
+	c.retrieval = enabled
+	c.mu.Unlock()
+
+	if enabled {
+		c.logger.Info("Retrieval enabled")
+	} else {
+		c.logger.Info("Retrieval disabled")
+	}
+}
+
+// retrieve returns the indexed chunks most relevant to query
+func (c *Client) retrieve(ctx context.Context, query string) ([]SearchResult, error) {
+	index, err := c.vectorIndex()
+	if err != nil {
+		return nil, err
+	}
+	if index.Len() == 0 {
+		return nil, nil
+	}
+	if model := index.EmbeddingModel(); model != c.config.EmbeddingModel {
+		return nil, fmt.Errorf("index was built with %s, re-index to use %s", model, c.config.EmbeddingModel)
+	}
+
+	vectors, err := c.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+
+	var relevant []SearchResult
+	for _, result := range index.Search(vectors[0], RetrievalTopK) {
+		if result.Score >= MinRetrievalScore {
+			relevant = append(relevant, result)
+		}
+	}
+	return relevant, nil
+}
+
+// withRetrievedContext prefixes the text of content with numbered excerpts
+// the model is asked to cite
+func withRetrievedContext(content MessageContent, results []SearchResult) MessageContent {
+	var prompt strings.Builder
+	prompt.WriteString("Use the numbered excerpts below when they are relevant to the question, and cite them as [n].\n\n")
+	for i, result := range results {
+		fmt.Fprintf(&prompt, "[%d] %s\n```\n%s\n```\n\n", i+1, result.Citation(), result.Text)
+	}
+	prompt.WriteString("Question: ")
+	prompt.WriteString(content.Text())
+
+	augmented := TextContent(prompt.String())
+	for _, part := range content {
+		if part.Type != ContentPartInputText {
+			augmented = append(augmented, part)
+		}
+	}
+	return augmented
+}
+
+// formatSources lists the citations of results, one per line
+func formatSources(results []SearchResult) string {
+	var sources strings.Builder
+	for i, result := range results {
+		fmt.Fprintf(&sources, "[%d] %s\n", i+1, result.Citation())
+	}
+	return sources.String()
+}
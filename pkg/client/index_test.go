@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// embeddingBackend embeds every text as the same vector
+type embeddingBackend struct {
+	fakeBackend
+}
+
+func (e *embeddingBackend) Embed(ctx context.Context, model string, inputs []string) ([][]float32, error) {
+	vectors := make([][]float32, len(inputs))
+	for i := range inputs {
+		vectors[i] = []float32{1, 0}
+	}
+	return vectors, nil
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float64
+	}{
+		{name: "same direction", a: []float32{1, 2}, b: []float32{2, 4}, want: 1},
+		{name: "orthogonal", a: []float32{1, 0}, b: []float32{0, 1}, want: 0},
+		{name: "opposite", a: []float32{1, 0}, b: []float32{-1, 0}, want: -1},
+		{name: "different lengths", a: []float32{1, 0}, b: []float32{1}, want: 0},
+		{name: "zero vector", a: []float32{0, 0}, b: []float32{1, 0}, want: 0},
+		{name: "empty", a: nil, b: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cosineSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("cosineSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkFile(t *testing.T) {
+	dir := t.TempDir()
+	lines := make([]string, IndexChunkLines+10)
+	for i := range lines {
+		lines[i] = "line"
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    [][2]int // Start and end line of every chunk
+	}{
+		{name: "empty", content: "", want: nil},
+		{name: "binary", content: "\x00\x01\x02", want: nil},
+		{name: "short", content: "one\ntwo\n", want: [][2]int{{1, 2}}},
+		{
+			name:    "overlapping chunks",
+			content: strings.Join(lines, "\n"),
+			want:    [][2]int{{1, IndexChunkLines}, {IndexChunkLines - IndexChunkOverlap + 1, IndexChunkLines + 10}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_"))
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			chunks, err := chunkFile(path)
+			if err != nil {
+				t.Fatalf("chunkFile() error = %v", err)
+			}
+			if len(chunks) != len(tt.want) {
+				t.Fatalf("chunkFile() returned %d chunks, want %d", len(chunks), len(tt.want))
+			}
+			for i, chunk := range chunks {
+				if chunk.StartLine != tt.want[i][0] || chunk.EndLine != tt.want[i][1] {
+					t.Errorf("chunk %d spans %d-%d, want %d-%d", i, chunk.StartLine, chunk.EndLine, tt.want[i][0], tt.want[i][1])
+				}
+			}
+		})
+	}
+}
+
+func TestIndexDirectoryRecoversFromCorruptIndex(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	dir, err := ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, IndexFileName), []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewClientWithBackend(context.Background(), DefaultClientConfig(), &embeddingBackend{}, nil)
+	if err != nil {
+		t.Fatalf("NewClientWithBackend() error = %v", err)
+	}
+
+	if _, err := c.retrieve(context.Background(), "question"); err == nil {
+		t.Fatal("retrieve() error = nil, want the corrupt index to be reported")
+	}
+
+	docs := t.TempDir()
+	if err := os.WriteFile(filepath.Join(docs, "notes.txt"), []byte("the answer is 42\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	count, err := c.IndexDirectory(context.Background(), docs)
+	if err != nil {
+		t.Fatalf("IndexDirectory() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("IndexDirectory() = %d chunks, want 1", count)
+	}
+
+	results, err := c.retrieve(context.Background(), "question")
+	if err != nil {
+		t.Fatalf("retrieve() after re-indexing error = %v", err)
+	}
+	if len(results) != 1 || results[0].Text != "the answer is 42" {
+		t.Errorf("retrieve() = %+v, want the indexed chunk", results)
+	}
+}
+
+func TestEmbeddingModelDefault(t *testing.T) {
+	// Configurations from before retrieval leave the embedding model empty
+	config := ClientConfig{
+		Host:            DefaultLMStudioHost,
+		Port:            DefaultLMStudioPort,
+		Scheme:          DefaultLMStudioScheme,
+		HTTPTimeout:     DefaultHTTPTimeout,
+		LogChannelSize:  DefaultLogChannelSize,
+		ModelAPI:        ModelAPIREST,
+		ChatAPI:         ChatAPIAuto,
+		ReasoningEffort: ReasoningEffortOff,
+	}
+	c, err := NewClientWithBackend(context.Background(), config, &embeddingBackend{}, nil)
+	if err != nil {
+		t.Fatalf("NewClientWithBackend() error = %v", err)
+	}
+	if model := c.GetConfig().EmbeddingModel; model != DefaultEmbeddingModelKey {
+		t.Errorf("EmbeddingModel = %q, want %q", model, DefaultEmbeddingModelKey)
+	}
+
+	if err := ValidateClientConfig(config); err != nil {
+		t.Errorf("ValidateClientConfig() error = %v without retrieval", err)
+	}
+	config.Retrieval = true
+	if err := ValidateClientConfig(config); err == nil {
+		t.Error("ValidateClientConfig() error = nil for retrieval without an embedding model")
+	}
+}
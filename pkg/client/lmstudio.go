@@ -0,0 +1,257 @@
+package client
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// LMStudioBackend drives an LM Studio server. Models are listed through the
+// native /api/v0 REST endpoints or the lms CLI depending on the configured
+// model API. Chat goes through the /v1/responses endpoint, or through
+// /v1/chat/completions when the server does not implement responses.
+type LMStudioBackend struct {
+	config       ClientConfig
+	logger       *Logger
+	httpClient   *http.Client
+	openAIClient *openai.Client
+	lmsAvailable bool
+	// useCompletions is set once the session switched to chat completions
+	useCompletions atomic.Bool
+	// sizes caches the model sizes of lms ls by model key, since the REST
+	// API does not report them. The map is replaced, never modified.
+	sizes   map[string]int64
+	sizesMu sync.Mutex
+}
+
+func NewLMStudioBackend(config ClientConfig, httpClient *http.Client, openAIClient *openai.Client, logger *Logger) (*LMStudioBackend, error) {
+	if config.ModelAPI == ModelAPICLI && !config.IsLocal() {
+		return nil, fmt.Errorf("model API %q only reaches the LM Studio on this machine, use %q for %s", ModelAPICLI, ModelAPIREST, config.Host)
+	}
+	_, lookErr := exec.LookPath("lms")
+	if lookErr != nil && config.ModelAPI == ModelAPICLI {
+		return nil, fmt.Errorf("lms command not found: %w", lookErr)
+	}
+	if lookErr != nil {
+		logger.Info("lms command not found, model loading and estimates are unavailable")
+	}
+
+	backend := &LMStudioBackend{
+		config:       config,
+		logger:       logger,
+		httpClient:   httpClient,
+		openAIClient: openAIClient,
+		lmsAvailable: lookErr == nil,
+	}
+	backend.useCompletions.Store(config.ChatAPI == ChatAPICompletions)
+
+	return backend, nil
+}
+
+// Name returns the backend name
+func (b *LMStudioBackend) Name() string {
+	return "LM Studio"
+}
+
+// StreamChat streams a chat response. In auto mode a 404 from /v1/responses
+// switches the rest of the session to /v1/chat/completions.
+func (b *LMStudioBackend) StreamChat(ctx context.Context, req ResponseRequest, callback func(string, string)) (StreamResult, error) {
+	if b.useCompletions.Load() {
+		return b.streamChatCompletions(ctx, withFullHistory(req), callback)
+	}
+
+	result, err := b.sendResponseStreamWithRetry(ctx, req, callback, 3)
+
+	var apiErr *APIError
+	if b.config.ChatAPI == ChatAPIAuto && errors.As(err, &apiErr) && apiErr.StatusCode == HTTPStatusNotFound && !isUnknownResponseError(err) {
+		b.logger.Warn("Server does not implement /v1/responses, switching to /v1/chat/completions")
+		b.useCompletions.Store(true)
+		return b.streamChatCompletions(ctx, withFullHistory(req), callback)
+	}
+	return result, err
+}
+
+// UsesChatCompletions reports whether chat currently goes through /v1/chat/completions
+func (b *LMStudioBackend) UsesChatCompletions() bool {
+	return b.useCompletions.Load()
+}
+
+// StoresResponses reports whether responses can be stored and chained by ID,
+// which chat completions cannot do
+func (b *LMStudioBackend) StoresResponses() bool {
+	return b.config.ChatAPI != ChatAPICompletions && !b.useCompletions.Load()
+}
+
+// withFullHistory turns a chained request back into a self-contained one for
+// APIs that cannot look up previous responses
+func withFullHistory(req ResponseRequest) ResponseRequest {
+	if req.PreviousResponseID != nil && req.history != nil {
+		req.Input = req.history
+	}
+	req.PreviousResponseID = nil
+	return req
+}
+
+// useREST reports whether model listing should go through the REST API
+func (b *LMStudioBackend) useREST() bool {
+	return b.config.ModelAPI == ModelAPIREST
+}
+
+// canFallBackToCLI reports whether lms can stand in for a failing REST API.
+// lms always talks to the LM Studio on this machine, so a remote server
+// must never be answered by it.
+func (b *LMStudioBackend) canFallBackToCLI() bool {
+	return b.lmsAvailable && b.config.IsLocal()
+}
+
+// requireCLI returns an error when an operation needs lms but it is
+// missing, or when lms would act on this machine instead of the server
+func (b *LMStudioBackend) requireCLI(operation string) error {
+	if !b.lmsAvailable {
+		return fmt.Errorf("%s requires the lms command: %w", operation, ErrNotSupported)
+	}
+	if !b.config.IsLocal() {
+		return fmt.Errorf("%s requires lms, which cannot reach the server at %s: %w", operation, b.config.Host, ErrNotSupported)
+	}
+	return nil
+}
+
+// CanManageModels reports why models cannot be loaded, unloaded, estimated
+// or downloaded, or nil when they can
+func (b *LMStudioBackend) CanManageModels() error {
+	return b.requireCLI("managing models")
+}
+
+func (b *LMStudioBackend) GetStatus() (Status, error) {
+	if !b.useREST() {
+		return b.cliGetStatus()
+	}
+
+	status, err := b.restGetStatus()
+	if status != StatusOn && b.canFallBackToCLI() {
+		return b.cliGetStatus()
+	}
+	return status, err
+}
+
+func (b *LMStudioBackend) GetDownloadedModels() ([]LMSDownloadedListItem, error) {
+	if !b.useREST() {
+		return b.cliGetDownloadedModels()
+	}
+
+	models, err := b.restGetDownloadedModels()
+	if err != nil && b.canFallBackToCLI() {
+		b.logger.Debug("REST model listing failed, falling back to lms: %v", err)
+		return b.cliGetDownloadedModels()
+	}
+	if err == nil && b.canFallBackToCLI() {
+		keys := make([]string, len(models))
+		for i, model := range models {
+			keys[i] = model.ModelKey
+		}
+		sizes := b.modelSizes(keys)
+		for i := range models {
+			models[i].SizeBytes = cmp.Or(models[i].SizeBytes, sizes[models[i].ModelKey])
+		}
+	}
+	return models, err
+}
+
+func (b *LMStudioBackend) GetLoadedModels() ([]LMSLoadedListItem, error) {
+	if !b.useREST() {
+		return b.cliGetLoadedModels()
+	}
+
+	models, err := b.restGetLoadedModels()
+	if err != nil && b.canFallBackToCLI() {
+		b.logger.Debug("REST loaded model listing failed, falling back to lms: %v", err)
+		return b.cliGetLoadedModels()
+	}
+	if err == nil && b.canFallBackToCLI() {
+		keys := make([]string, len(models))
+		for i, model := range models {
+			keys[i] = model.ModelKey
+		}
+		sizes := b.modelSizes(keys)
+		for i := range models {
+			models[i].SizeBytes = cmp.Or(models[i].SizeBytes, sizes[models[i].ModelKey])
+		}
+	}
+	return models, err
+}
+
+// modelSizes returns the sizes lms ls reports, by model key. lms only runs
+// again when one of keys was not listed before, sizes do not change.
+func (b *LMStudioBackend) modelSizes(keys []string) map[string]int64 {
+	b.sizesMu.Lock()
+	defer b.sizesMu.Unlock()
+
+	known := func(key string) bool {
+		_, ok := b.sizes[key]
+		return ok
+	}
+	if !slices.ContainsFunc(keys, func(key string) bool { return !known(key) }) {
+		return b.sizes
+	}
+
+	models, err := b.cliGetDownloadedModels()
+	if err != nil {
+		b.logger.Debug("Model sizes are unavailable: %v", err)
+		return b.sizes
+	}
+	sizes := make(map[string]int64, len(models)+len(keys))
+	for _, key := range keys {
+		sizes[key] = 0 // Not listed by lms, do not ask again
+	}
+	for _, model := range models {
+		sizes[model.ModelKey] = model.SizeBytes
+	}
+	b.sizes = sizes
+	return sizes
+}
+
+func (b *LMStudioBackend) DownloadModel(ctx context.Context, model string, progress func(DownloadProgress)) error {
+	if err := b.requireCLI("downloading models"); err != nil {
+		return err
+	}
+	return b.cliDownloadModel(ctx, model, progress)
+}
+
+func (b *LMStudioBackend) GetModelEstimate(identifier string) (ModelEstimate, error) {
+	if err := b.requireCLI("load estimates"); err != nil {
+		return ModelEstimate{Status: StatusUnknown}, err
+	}
+	return b.cliGetModelEstimate(identifier)
+}
+
+// The /api/v0 endpoints have no explicit load and unload operations, so
+// model lifecycle always goes through lms.
+
+func (b *LMStudioBackend) LoadModel(modelID string, options LoadOptions) error {
+	if err := b.requireCLI("loading models"); err != nil {
+		return err
+	}
+	return b.cliLoadModel(modelID, options)
+}
+
+func (b *LMStudioBackend) UnloadModel(modelID string) error {
+	if err := b.requireCLI("unloading models"); err != nil {
+		return err
+	}
+	return b.cliUnloadModel(modelID)
+}
+
+func (b *LMStudioBackend) UnloadAllModels() error {
+	if err := b.requireCLI("unloading models"); err != nil {
+		return err
+	}
+	return b.cliUnloadAllModels()
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLMStudioBackendCanManageModels(t *testing.T) {
+	tests := []struct {
+		host         string
+		lmsAvailable bool
+		wantErr      bool
+	}{
+		{host: "localhost", lmsAvailable: true},
+		{host: "127.0.0.1", lmsAvailable: true},
+		{host: "[::1]", lmsAvailable: true},
+		{host: "studio.localhost", lmsAvailable: true},
+		{host: "0.0.0.0", lmsAvailable: true},
+		{host: "localhost", lmsAvailable: false, wantErr: true},
+		// lms would load models into this machine, not the server
+		{host: "10.0.0.12", lmsAvailable: true, wantErr: true},
+		{host: "llm.example.com", lmsAvailable: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		config := DefaultClientConfig()
+		config.Host = tt.host
+		backend := &LMStudioBackend{config: config, lmsAvailable: tt.lmsAvailable}
+
+		err := backend.CanManageModels()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CanManageModels() on %s with lms = %v: error = %v, want error %v", tt.host, tt.lmsAvailable, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrNotSupported) {
+			t.Errorf("CanManageModels() on %s error = %v, want ErrNotSupported", tt.host, err)
+		}
+		if !tt.wantErr {
+			continue
+		}
+		if loadErr := backend.LoadModel("qwen/qwen3-8b", LoadOptions{}); !errors.Is(loadErr, ErrNotSupported) {
+			t.Errorf("LoadModel() on %s error = %v, want ErrNotSupported", tt.host, loadErr)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GPU offload settings accepted besides a ratio
+const (
+	GPUOffloadMax = "max"
+	GPUOffloadOff = "off"
+)
+
+// LoadOptions tune how a model is loaded. Zero values keep the server
+// defaults.
+type LoadOptions struct {
+	ContextLength int    `json:"context_length,omitempty"`
+	GPU           string `json:"gpu,omitempty"`         // GPUOffloadMax, GPUOffloadOff or a ratio between 0 and 1
+	TTL           int    `json:"ttl_seconds,omitempty"` // Idle seconds before the model unloads itself
+	Identifier    string `json:"identifier,omitempty"`  // Identifier to load the model under instead of its key
+	Parallel      int    `json:"parallel,omitempty"`    // Requests the model serves at once
+}
+
+// IsZero reports whether every option keeps its default
+func (o LoadOptions) IsZero() bool {
+	return o == LoadOptions{}
+}
+
+// lmsArgs returns the options as lms load flags
+func (o LoadOptions) lmsArgs() []string {
+	var args []string
+	if o.ContextLength > 0 {
+		args = append(args, "--context-length", strconv.Itoa(o.ContextLength))
+	}
+	if o.GPU != "" {
+		args = append(args, "--gpu", o.GPU)
+	}
+	if o.TTL > 0 {
+		args = append(args, "--ttl", strconv.Itoa(o.TTL))
+	}
+	if o.Identifier != "" {
+		args = append(args, "--identifier", o.Identifier)
+	}
+	if o.Parallel > 0 {
+		args = append(args, "--parallel", strconv.Itoa(o.Parallel))
+	}
+	return args
+}
+
+// ValidateLoadOptions checks the options, bounding the context length by
+// maxContextLength when it is known (non-zero)
+func ValidateLoadOptions(options LoadOptions, maxContextLength int) error {
+	if options.ContextLength < 0 {
+		return ValidationError{
+			Field:   "context_length",
+			Value:   strconv.Itoa(options.ContextLength),
+			Message: "context length cannot be negative",
+		}
+	}
+	if maxContextLength > 0 && options.ContextLength > maxContextLength {
+		return ValidationError{
+			Field:   "context_length",
+			Value:   strconv.Itoa(options.ContextLength),
+			Message: fmt.Sprintf("context length cannot exceed the model maximum of %d", maxContextLength),
+		}
+	}
+
+	if err := ValidateGPUOffload(options.GPU); err != nil {
+		return err
+	}
+
+	if options.TTL < 0 {
+		return ValidationError{
+			Field:   "ttl",
+			Value:   strconv.Itoa(options.TTL),
+			Message: "TTL cannot be negative",
+		}
+	}
+
+	if options.Identifier != "" {
+		if err := ValidateModelID(options.Identifier); err != nil {
+			return fmt.Errorf("invalid identifier: %w", err)
+		}
+		if strings.HasPrefix(options.Identifier, "-") {
+			return ValidationError{
+				Field:   "identifier",
+				Value:   options.Identifier,
+				Message: "identifier cannot start with a dash",
+			}
+		}
+	}
+
+	if options.Parallel < 0 {
+		return ValidationError{
+			Field:   "parallel",
+			Value:   strconv.Itoa(options.Parallel),
+			Message: "parallel slots cannot be negative",
+		}
+	}
+	return nil
+}
+
+// ValidateGPUOffload accepts "max", "off", a ratio between 0 and 1, or an
+// empty value for the default
+func ValidateGPUOffload(gpu string) error {
+	if gpu == "" || gpu == GPUOffloadMax || gpu == GPUOffloadOff {
+		return nil
+	}
+	ratio, err := strconv.ParseFloat(gpu, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		return ValidationError{
+			Field:   "gpu",
+			Value:   gpu,
+			Message: "GPU offload must be max, off or a ratio between 0 and 1",
+		}
+	}
+	return nil
+}
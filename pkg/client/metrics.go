@@ -0,0 +1,80 @@
+package client
+
+import "time"
+
+// Usage is the token accounting reported by the server for a response
+type Usage struct {
+	InputTokens     int
+	OutputTokens    int
+	ReasoningTokens int // Included in OutputTokens
+}
+
+// StreamMetrics describes how fast a response was generated
+type StreamMetrics struct {
+	Usage
+	// TimeToFirstToken is measured from sending the request to the first
+	// streamed chunk of output or reasoning
+	TimeToFirstToken time.Duration
+	// GenerationTime is measured from the first streamed chunk to the end
+	// of the stream
+	GenerationTime time.Duration
+}
+
+// TokensPerSecond returns the output token throughput, or 0 when unknown
+func (m StreamMetrics) TokensPerSecond() float64 {
+	if m.OutputTokens == 0 || m.GenerationTime <= 0 {
+		return 0
+	}
+	return float64(m.OutputTokens) / m.GenerationTime.Seconds()
+}
+
+// add accumulates the metrics of a follow-up request, such as the rounds
+// of a tool calling loop. The time to first token of the first request is kept.
+func (m *StreamMetrics) add(other StreamMetrics) {
+	if m.TimeToFirstToken == 0 {
+		m.TimeToFirstToken = other.TimeToFirstToken
+	}
+	m.InputTokens += other.InputTokens
+	m.OutputTokens += other.OutputTokens
+	m.ReasoningTokens += other.ReasoningTokens
+	m.GenerationTime += other.GenerationTime
+}
+
+// streamTimer measures a single streamed request
+type streamTimer struct {
+	start      time.Time
+	firstChunk time.Time
+	chunks     int
+}
+
+func newStreamTimer() *streamTimer {
+	return &streamTimer{start: time.Now()}
+}
+
+// wrap returns a callback that records chunk timings before forwarding them
+func (t *streamTimer) wrap(callback func(string, string)) func(string, string) {
+	return func(text, contentType string) {
+		if contentType == ContentTypeOutput || contentType == ContentTypeReasoning {
+			if t.firstChunk.IsZero() {
+				t.firstChunk = time.Now()
+			}
+			t.chunks++
+		}
+		callback(text, contentType)
+	}
+}
+
+// metrics finishes the measurement. Servers that report no usage get the
+// number of streamed chunks as an approximate output token count.
+func (t *streamTimer) metrics(usage Usage) StreamMetrics {
+	if usage.OutputTokens == 0 {
+		usage.OutputTokens = t.chunks
+	}
+
+	metrics := StreamMetrics{Usage: usage}
+	if !t.firstChunk.IsZero() {
+		metrics.TimeToFirstToken = t.firstChunk.Sub(t.start)
+		metrics.GenerationTime = time.Since(t.firstChunk)
+	}
+	return metrics
+}
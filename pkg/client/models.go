@@ -1,6 +1,8 @@
 package client
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 )
 
@@ -18,21 +20,157 @@ func (c *Client) GetActiveModel() (string, error) {
 }
 
 func (c *Client) GetActiveModelByID(modelID string) (string, error) {
-	models, err := c.GetLoadedModels()
+	model, err := c.GetLoadedModelByID(modelID)
 	if err != nil {
 		return "", err
 	}
+	return model.Identifier, nil
+}
+
+// GetLoadedModelByID returns the loaded model with the given identifier,
+// falling back to the first loaded model
+func (c *Client) GetLoadedModelByID(modelID string) (LMSLoadedListItem, error) {
+	models, err := c.GetLoadedModels()
+	if err != nil {
+		return LMSLoadedListItem{}, err
+	}
 
 	for _, model := range models {
 		if model.Identifier == modelID {
-
-			return model.Identifier, nil
+			return model, nil
 		}
 	}
 
 	if len(models) > 0 {
-		return models[0].Identifier, nil
+		return models[0], nil
+	}
+
+	return LMSLoadedListItem{}, fmt.Errorf("no models are currently loaded")
+}
+
+func (c *Client) GetStatus() (Status, error) {
+	if c.IsClosed() {
+		return StatusUnknown, fmt.Errorf("client is closed")
+	}
+	return c.backend.GetStatus()
+}
+
+// CanManageModels reports why models cannot be loaded, unloaded, estimated
+// or downloaded on the current server, or nil when they can
+func (c *Client) CanManageModels() error {
+	if c.IsClosed() {
+		return fmt.Errorf("client is closed")
 	}
+	manager, ok := c.backend.(ModelManager)
+	if !ok {
+		return nil
+	}
+	return manager.CanManageModels()
+}
+
+// GetModelEstimate predicts whether a downloaded model fits, with the
+// memory it needs when the backend reports it. A model is available when
+// the backend cannot estimate, so that estimates never block loading.
+func (c *Client) GetModelEstimate(identifier string) (ModelEstimate, error) {
+	estimate, err := c.EstimateModel(identifier)
+	if errors.Is(err, ErrNotSupported) {
+		return ModelEstimate{Status: StatusAvailable}, nil
+	}
+	return estimate, err
+}
+
+// EstimateModel is GetModelEstimate for callers that must tell a real
+// estimate apart: it returns ErrNotSupported when the backend cannot
+// estimate, without a lms binary or on a remote server.
+func (c *Client) EstimateModel(identifier string) (ModelEstimate, error) {
+	estimator, ok := c.backend.(Estimator)
+	if !ok {
+		return ModelEstimate{Status: StatusUnknown}, fmt.Errorf("load estimates: %w", ErrNotSupported)
+	}
+	return estimator.GetModelEstimate(identifier)
+}
+
+func (c *Client) GetDownloadedModelsWithoutEstimates() ([]LMSDownloadedListItem, error) {
+	if c.IsClosed() {
+		return nil, fmt.Errorf("client is closed")
+	}
+	return c.backend.GetDownloadedModels()
+}
 
-	return "", fmt.Errorf("no models are currently loaded")
+func (c *Client) GetDownloadedModels() ([]LMSDownloadedListItem, error) {
+	if c.IsClosed() {
+		return nil, fmt.Errorf("client is closed")
+	}
+
+	downloadedList, err := c.backend.GetDownloadedModels()
+	if err != nil {
+		return []LMSDownloadedListItem{}, err
+	}
+
+	// A failed estimate marks its model only, the rest of the list stands
+	failed, firstErr := 0, error(nil)
+	for result := range c.EstimateModels(downloadedList) {
+		ApplyEstimate(downloadedList, result)
+		if result.Err != nil {
+			failed++
+			firstErr = cmp.Or(firstErr, result.Err)
+		}
+	}
+	if err := EstimateFailure(failed, firstErr); err != nil {
+		c.logger.Warn("%v", err)
+	}
+	return downloadedList, nil
+}
+
+func (c *Client) GetLoadedModels() ([]LMSLoadedListItem, error) {
+	if c.IsClosed() {
+		return nil, fmt.Errorf("client is closed")
+	}
+	return c.backend.GetLoadedModels()
+}
+
+// LoadModel loads a downloaded model. The context length of options is
+// bounded by the model maximum by the caller, which knows the model.
+func (c *Client) LoadModel(modelID string, options LoadOptions) error {
+	if err := ValidateModelID(modelID); err != nil {
+		return fmt.Errorf("invalid model ID: %w", err)
+	}
+
+	if err := ValidateLoadOptions(options, 0); err != nil {
+		return fmt.Errorf("invalid load options: %w", err)
+	}
+
+	if c.IsClosed() {
+		return fmt.Errorf("client is closed")
+	}
+
+	if err := c.backend.LoadModel(modelID, options); err != nil {
+		c.logger.Error("Failed to load model %s: %v", modelID, err)
+		return fmt.Errorf("failed to load model: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) UnloadModel(modelKey string) error {
+	if err := ValidateModelID(modelKey); err != nil {
+		return fmt.Errorf("invalid model key: %w", err)
+	}
+
+	if c.IsClosed() {
+		return fmt.Errorf("client is closed")
+	}
+
+	if err := c.backend.UnloadModel(modelKey); err != nil {
+		c.logger.Error("Failed to unload model %s: %v", modelKey, err)
+		return fmt.Errorf("failed to unload model: %w", err)
+	}
+	c.logger.Info("Successfully unloaded model: %s", modelKey)
+	return nil
+}
+
+func (c *Client) UnloadAllModels() error {
+	if c.IsClosed() {
+		return fmt.Errorf("client is closed")
+	}
+	return c.backend.UnloadAllModels()
 }
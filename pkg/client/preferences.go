@@ -0,0 +1,216 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const (
+	AppConfigDirName    = "lazylms"
+	PreferencesFileName = "preferences.json"
+)
+
+// ConfigDir returns the lazylms configuration directory, honouring
+// XDG_CONFIG_HOME and defaulting to ~/.config/lazylms
+func ConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, AppConfigDirName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", AppConfigDirName), nil
+}
+
+// Preferences are choices remembered across sessions
+type Preferences struct {
+	// ReasoningEffort is the last effort chosen for each model identifier
+	ReasoningEffort map[string]string `json:"reasoning_effort,omitempty"`
+	// Presets are named sampling parameters saved by the user
+	Presets map[string]SamplingParams `json:"presets,omitempty"`
+	// LoadOptions are the last options a model was loaded with, by model key
+	LoadOptions map[string]LoadOptions `json:"load_options,omitempty"`
+	// LastUsed is when each model key was last loaded or chatted with, in
+	// Unix milliseconds like the lastUsedTime of lms ps
+	LastUsed map[string]int64 `json:"last_used,omitempty"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// LoadPreferences reads the preferences file, returning empty preferences
+// when it does not exist yet
+func LoadPreferences() (*Preferences, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return &Preferences{}, err
+	}
+
+	prefs := &Preferences{path: filepath.Join(dir, PreferencesFileName)}
+
+	data, err := os.ReadFile(prefs.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return prefs, nil
+	}
+	if err != nil {
+		return prefs, fmt.Errorf("failed to read preferences: %w", err)
+	}
+
+	if err := json.Unmarshal(data, prefs); err != nil {
+		return prefs, fmt.Errorf("failed to parse preferences %s: %w", prefs.path, err)
+	}
+	return prefs, nil
+}
+
+// Save writes the preferences back to disk
+func (p *Preferences) Save() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.path == "" {
+		return fmt.Errorf("preferences have no file path")
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode preferences: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated file
+	tmpPath := p.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write preferences: %w", err)
+	}
+	if err := os.Rename(tmpPath, p.path); err != nil {
+		return fmt.Errorf("failed to write preferences: %w", err)
+	}
+	return nil
+}
+
+// ReasoningEffortFor returns the remembered reasoning effort for a model
+func (p *Preferences) ReasoningEffortFor(modelID string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	effort, ok := p.ReasoningEffort[modelID]
+	return effort, ok
+}
+
+// SetReasoningEffort remembers the reasoning effort chosen for a model
+func (p *Preferences) SetReasoningEffort(modelID, effort string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ReasoningEffort == nil {
+		p.ReasoningEffort = make(map[string]string)
+	}
+	p.ReasoningEffort[modelID] = effort
+}
+
+// BuiltinPresets returns the presets available without any saved preferences
+func BuiltinPresets() map[string]SamplingParams {
+	precise, creative := 0.2, 1.0
+	preciseTopP, creativeTopP := 0.9, 0.95
+	return map[string]SamplingParams{
+		"precise":  {Temperature: &precise, TopP: &preciseTopP},
+		"creative": {Temperature: &creative, TopP: &creativeTopP},
+	}
+}
+
+// PresetNames returns the names of the builtin and saved presets, sorted
+func (p *Preferences) PresetNames() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	names := make([]string, 0, len(p.Presets)+2)
+	for name := range BuiltinPresets() {
+		if _, saved := p.Presets[name]; !saved {
+			names = append(names, name)
+		}
+	}
+	for name := range p.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Preset returns a saved preset, falling back to the builtin presets
+func (p *Preferences) Preset(name string) (SamplingParams, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if params, ok := p.Presets[name]; ok {
+		return params, true
+	}
+	params, ok := BuiltinPresets()[name]
+	return params, ok
+}
+
+// SavePreset stores named sampling parameters
+func (p *Preferences) SavePreset(name string, params SamplingParams) error {
+	if err := ValidatePresetName(name); err != nil {
+		return err
+	}
+	if err := ValidateSamplingParams(params); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Presets == nil {
+		p.Presets = make(map[string]SamplingParams)
+	}
+	p.Presets[name] = params
+	return nil
+}
+
+// LoadOptionsFor returns the options a model was last loaded with
+func (p *Preferences) LoadOptionsFor(modelKey string) (LoadOptions, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	options, ok := p.LoadOptions[modelKey]
+	return options, ok
+}
+
+// SetLoadOptions remembers the options a model was loaded with
+func (p *Preferences) SetLoadOptions(modelKey string, options LoadOptions) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.LoadOptions == nil {
+		p.LoadOptions = make(map[string]LoadOptions)
+	}
+	p.LoadOptions[modelKey] = options
+}
+
+// LastUsedAt returns when a model was last used, 0 when never
+func (p *Preferences) LastUsedAt(modelKey string) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.LastUsed[modelKey]
+}
+
+// SetLastUsed remembers when a model was used unless a later use is known.
+// It reports whether the preferences changed.
+func (p *Preferences) SetLastUsed(modelKey string, unixMilli int64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if unixMilli <= p.LastUsed[modelKey] {
+		return false
+	}
+	if p.LastUsed == nil {
+		p.LastUsed = make(map[string]int64)
+	}
+	p.LastUsed[modelKey] = unixMilli
+	return true
+}
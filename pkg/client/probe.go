@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ServerProbe is the result of checking whether a server answers
+type ServerProbe struct {
+	Reachable bool
+	Latency   time.Duration
+	Err       error
+}
+
+// ProbeServer checks whether the server described by config answers on
+// its OpenAI-compatible model list, without creating a client
+func ProbeServer(ctx context.Context, config ClientConfig) ServerProbe {
+	ctx, cancel := context.WithTimeout(ctx, DefaultProbeTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", config.GetAPIURL()+"/models", nil)
+	if err != nil {
+		return ServerProbe{Err: fmt.Errorf("create request: %w", err)}
+	}
+	httpReq.Header.Set("Accept", "application/json")
+
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return ServerProbe{Err: err}
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return ServerProbe{Err: fmt.Errorf("http request: %w", err)}
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start)
+
+	if resp.StatusCode != HTTPStatusOK {
+		return ServerProbe{Latency: latency, Err: newAPIError(resp)}
+	}
+	return ServerProbe{Reachable: true, Latency: latency}
+}
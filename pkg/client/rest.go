@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// fetchRESTModels lists every model known to the server via /api/v0/models
+func (b *LMStudioBackend) fetchRESTModels() ([]Model, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRESTTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", b.config.GetFullURL()+"/api/v0/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != HTTPStatusOK {
+		return nil, fmt.Errorf("api error: %s", resp.Status)
+	}
+
+	var modelList ModelListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&modelList); err != nil {
+		return nil, fmt.Errorf("Failed to parse JSON output: %w", err)
+	}
+
+	return modelList.Data, nil
+}
+
+func (b *LMStudioBackend) restGetStatus() (Status, error) {
+	if _, err := b.fetchRESTModels(); err != nil {
+		return StatusOff, nil
+	}
+	return StatusOn, nil
+}
+
+func (b *LMStudioBackend) restGetDownloadedModels() ([]LMSDownloadedListItem, error) {
+	models, err := b.fetchRESTModels()
+	if err != nil {
+		return []LMSDownloadedListItem{}, err
+	}
+
+	var downloadedList []LMSDownloadedListItem
+	for _, model := range models {
+		if model.ID == DefaultEmbeddingModelKey {
+			continue
+		}
+		downloadedList = append(downloadedList, LMSDownloadedListItem{
+			Type:             model.Type,
+			ModelKey:         model.ID,
+			Format:           model.CompatibilityType,
+			DisplayName:      model.ID,
+			Publisher:        model.Publisher,
+			Architecture:     model.Arch,
+			Quantization:     Quantization{Name: model.Quantization},
+			MaxContextLength: model.MaxContextLength,
+			CanLoad:          true,
+		})
+	}
+
+	return downloadedList, nil
+}
+
+func (b *LMStudioBackend) restGetLoadedModels() ([]LMSLoadedListItem, error) {
+	models, err := b.fetchRESTModels()
+	if err != nil {
+		return []LMSLoadedListItem{}, err
+	}
+
+	loadedList := []LMSLoadedListItem{}
+	for _, model := range models {
+		if model.State != "loaded" {
+			continue
+		}
+		loadedList = append(loadedList, LMSLoadedListItem{
+			Type:              model.Type,
+			ModelKey:          model.ID,
+			Format:            model.CompatibilityType,
+			DisplayName:       model.ID,
+			Publisher:         model.Publisher,
+			Architecture:      model.Arch,
+			Quantization:      Quantization{Name: model.Quantization},
+			Identifier:        model.ID,
+			Vision:            model.Type == "vlm",
+			TrainedForToolUse: slices.Contains(model.Capabilities, "tool_use"),
+			MaxContextLength:  model.MaxContextLength,
+			ContextLength:     model.LoadedContextLength,
+			Status:            model.State,
+		})
+	}
+
+	return loadedList, nil
+}
@@ -0,0 +1,319 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONSchema is a schema the model output has to follow. Validation covers
+// the commonly used subset of JSON Schema: type, enum, const, properties,
+// required, additionalProperties, items, anyOf, oneOf, allOf and the length
+// and range keywords. $ref is not resolved.
+type JSONSchema struct {
+	Name string
+	Raw  json.RawMessage
+	root map[string]any
+}
+
+// SchemaValidation is the result of checking an output against a schema
+type SchemaValidation struct {
+	Schema     string
+	Violations []string
+}
+
+// Valid reports whether the output matched the schema
+func (v SchemaValidation) Valid() bool {
+	return len(v.Violations) == 0
+}
+
+var schemaNamePattern = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// LoadJSONSchema reads a schema file. The schema is named after the file.
+func LoadJSONSchema(path string) (*JSONSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return ParseJSONSchema(name, data)
+}
+
+// ParseJSONSchema parses a schema document
+func ParseJSONSchema(name string, data []byte) (*JSONSchema, error) {
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("schema is not a JSON object: %w", err)
+	}
+
+	name = schemaNamePattern.ReplaceAllString(name, "_")
+	if name == "" {
+		name = "output"
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+
+	return &JSONSchema{Name: name, Raw: compact.Bytes(), root: root}, nil
+}
+
+// Validate checks output against the schema. Output that is not JSON is a
+// single violation.
+func (s *JSONSchema) Validate(output string) SchemaValidation {
+	result := SchemaValidation{Schema: s.Name}
+
+	var value any
+	decoder := json.NewDecoder(strings.NewReader(StripCodeFence(output)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		result.Violations = []string{fmt.Sprintf("output is not valid JSON: %v", err)}
+		return result
+	}
+
+	result.Violations = validateSchema(s.root, value, "$")
+	return result
+}
+
+// StripCodeFence removes a markdown code fence some models wrap JSON in,
+// leaving the payload that is validated and displayed
+func StripCodeFence(output string) string {
+	output = strings.TrimSpace(output)
+	if !strings.HasPrefix(output, "```") {
+		return output
+	}
+	output = strings.TrimPrefix(output, "```")
+	if newline := strings.IndexByte(output, '\n'); newline >= 0 {
+		output = output[newline+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(output), "```"))
+}
+
+func validateSchema(schema map[string]any, value any, path string) []string {
+	var violations []string
+	fail := func(format string, args ...any) {
+		violations = append(violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if types, ok := schemaTypes(schema["type"]); ok {
+		if !slices.ContainsFunc(types, func(t string) bool { return matchesType(t, value) }) {
+			fail("expected %s, got %s", strings.Join(types, " or "), jsonTypeName(value))
+			return violations
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		if !slices.ContainsFunc(enum, func(option any) bool { return jsonEqual(option, value) }) {
+			fail("value is not one of the allowed values")
+		}
+	}
+
+	if constant, ok := schema["const"]; ok && !jsonEqual(constant, value) {
+		fail("value does not match the constant")
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		violations = append(violations, validateObject(schema, v, path)...)
+	case []any:
+		violations = append(violations, validateArray(schema, v, path)...)
+	case string:
+		length := utf8.RuneCountInString(v)
+		if limit, ok := schemaNumber(schema["minLength"]); ok && float64(length) < limit {
+			fail("string is shorter than %g characters", limit)
+		}
+		if limit, ok := schemaNumber(schema["maxLength"]); ok && float64(length) > limit {
+			fail("string is longer than %g characters", limit)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				fail("string does not match pattern %s", pattern)
+			}
+		}
+	case json.Number:
+		number, _ := v.Float64()
+		if limit, ok := schemaNumber(schema["minimum"]); ok && number < limit {
+			fail("%s is less than the minimum %g", v, limit)
+		}
+		if limit, ok := schemaNumber(schema["maximum"]); ok && number > limit {
+			fail("%s is greater than the maximum %g", v, limit)
+		}
+		if limit, ok := schemaNumber(schema["exclusiveMinimum"]); ok && number <= limit {
+			fail("%s is not greater than %g", v, limit)
+		}
+		if limit, ok := schemaNumber(schema["exclusiveMaximum"]); ok && number >= limit {
+			fail("%s is not less than %g", v, limit)
+		}
+	}
+
+	if subschemas, ok := schema["allOf"].([]any); ok {
+		for _, sub := range subschemas {
+			if subschema, ok := sub.(map[string]any); ok {
+				violations = append(violations, validateSchema(subschema, value, path)...)
+			}
+		}
+	}
+	if subschemas, ok := schema["anyOf"].([]any); ok && countMatches(subschemas, value, path) == 0 {
+		fail("value matches none of anyOf")
+	}
+	if subschemas, ok := schema["oneOf"].([]any); ok {
+		if matches := countMatches(subschemas, value, path); matches != 1 {
+			fail("value matches %d of oneOf, expected exactly 1", matches)
+		}
+	}
+
+	return violations
+}
+
+func validateObject(schema map[string]any, object map[string]any, path string) []string {
+	var violations []string
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, present := object[key]; !present {
+					violations = append(violations, fmt.Sprintf("%s: missing required property %q", path, key))
+				}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+
+	// Sort keys so violations are reported in a stable order
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childPath := path + "." + key
+		if property, ok := properties[key].(map[string]any); ok {
+			violations = append(violations, validateSchema(property, object[key], childPath)...)
+			continue
+		}
+		if _, declared := properties[key]; declared {
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				violations = append(violations, fmt.Sprintf("%s: unexpected property %q", path, key))
+			}
+		case map[string]any:
+			violations = append(violations, validateSchema(additional, object[key], childPath)...)
+		}
+	}
+
+	return violations
+}
+
+func validateArray(schema map[string]any, array []any, path string) []string {
+	var violations []string
+
+	if limit, ok := schemaNumber(schema["minItems"]); ok && float64(len(array)) < limit {
+		violations = append(violations, fmt.Sprintf("%s: array has fewer than %g items", path, limit))
+	}
+	if limit, ok := schemaNumber(schema["maxItems"]); ok && float64(len(array)) > limit {
+		violations = append(violations, fmt.Sprintf("%s: array has more than %g items", path, limit))
+	}
+
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range array {
+			violations = append(violations, validateSchema(items, item, path+"["+strconv.Itoa(i)+"]")...)
+		}
+	}
+
+	return violations
+}
+
+func countMatches(subschemas []any, value any, path string) int {
+	matches := 0
+	for _, sub := range subschemas {
+		if subschema, ok := sub.(map[string]any); ok && len(validateSchema(subschema, value, path)) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func schemaTypes(raw any) ([]string, bool) {
+	switch t := raw.(type) {
+	case string:
+		return []string{t}, true
+	case []any:
+		var types []string
+		for _, item := range t {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types, len(types) > 0
+	}
+	return nil, false
+}
+
+func schemaNumber(raw any) (float64, bool) {
+	number, ok := raw.(float64)
+	return number, ok
+}
+
+func matchesType(schemaType string, value any) bool {
+	switch schemaType {
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := number.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	default:
+		return jsonTypeName(value) == schemaType
+	}
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "unknown"
+}
+
+// jsonEqual compares a schema value with a decoded output value
+func jsonEqual(schemaValue, value any) bool {
+	a, errA := json.Marshal(schemaValue)
+	b, errB := json.Marshal(value)
+	if errA != nil || errB != nil {
+		return false
+	}
+	var normalizedA, normalizedB any
+	json.Unmarshal(a, &normalizedA)
+	json.Unmarshal(b, &normalizedB)
+	return fmt.Sprint(normalizedA) == fmt.Sprint(normalizedB)
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+)
+
+const personSchema = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "maxLength": 10},
+		"age": {"type": "integer", "minimum": 0, "maximum": 150},
+		"email": {"type": "string", "pattern": "^[^@]+@[^@]+$"},
+		"role": {"enum": ["admin", "user"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+		"score": {"type": ["number", "null"], "exclusiveMinimum": 0}
+	},
+	"required": ["name", "age"],
+	"additionalProperties": false
+}`
+
+func TestJSONSchemaValidate(t *testing.T) {
+	schema, err := ParseJSONSchema("person", []byte(personSchema))
+	if err != nil {
+		t.Fatalf("ParseJSONSchema() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{name: "valid", output: `{"name":"Ada","age":36}`},
+		{name: "valid in a code fence", output: "```json\n{\"name\":\"Ada\",\"age\":36}\n```"},
+		{name: "nullable number", output: `{"name":"Ada","age":36,"score":null}`},
+		{name: "missing required", output: `{"name":"Ada"}`, want: []string{`$: missing required property "age"`}},
+		{name: "wrong type", output: `{"name":7,"age":36}`, want: []string{"$.name: expected string, got number"}},
+		{name: "integer with a fraction", output: `{"name":"Ada","age":36.5}`, want: []string{"$.age: expected integer, got number"}},
+		{name: "out of range", output: `{"name":"Ada","age":200}`, want: []string{"$.age: 200 is greater than the maximum 150"}},
+		{name: "exclusive minimum", output: `{"name":"Ada","age":1,"score":0}`, want: []string{"$.score: 0 is not greater than 0"}},
+		{name: "too long", output: `{"name":"Ada Lovelace King","age":36}`, want: []string{"$.name: string is longer than 10 characters"}},
+		{name: "too short", output: `{"name":"","age":36}`, want: []string{"$.name: string is shorter than 1 characters"}},
+		{name: "pattern", output: `{"name":"Ada","age":36,"email":"nope"}`, want: []string{"$.email: string does not match pattern ^[^@]+@[^@]+$"}},
+		{name: "enum", output: `{"name":"Ada","age":36,"role":"root"}`, want: []string{"$.role: value is not one of the allowed values"}},
+		{
+			name:   "array items and length",
+			output: `{"name":"Ada","age":36,"tags":["a",1,"c"]}`,
+			want:   []string{"$.tags: array has more than 2 items", "$.tags[1]: expected string, got number"},
+		},
+		{name: "additional property", output: `{"name":"Ada","age":36,"extra":true}`, want: []string{`$: unexpected property "extra"`}},
+		{name: "not an object", output: `[1,2]`, want: []string{"$: expected object, got array"}},
+		{name: "not JSON", output: `name: Ada`, want: []string{"output is not valid JSON: invalid character 'a' in literal null (expecting 'u')"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := schema.Validate(tt.output)
+			if got.Schema != "person" {
+				t.Errorf("Validate().Schema = %q, want %q", got.Schema, "person")
+			}
+			if !reflect.DeepEqual(got.Violations, tt.want) {
+				t.Errorf("Validate() violations = %q, want %q", got.Violations, tt.want)
+			}
+			if got.Valid() != (len(tt.want) == 0) {
+				t.Errorf("Valid() = %v with violations %q", got.Valid(), got.Violations)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaCombinators(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		output string
+		want   []string
+	}{
+		{name: "anyOf match", schema: `{"anyOf":[{"type":"string"},{"type":"integer"}]}`, output: `3`},
+		{name: "anyOf no match", schema: `{"anyOf":[{"type":"string"},{"type":"integer"}]}`, output: `true`, want: []string{"$: value matches none of anyOf"}},
+		{name: "oneOf two matches", schema: `{"oneOf":[{"type":"number"},{"type":"integer"}]}`, output: `3`, want: []string{"$: value matches 2 of oneOf, expected exactly 1"}},
+		{name: "allOf", schema: `{"allOf":[{"type":"string"},{"maxLength":2}]}`, output: `"abc"`, want: []string{"$: string is longer than 2 characters"}},
+		{name: "const", schema: `{"const":{"a":1}}`, output: `{"a":2}`, want: []string{"$: value does not match the constant"}},
+		{name: "additional properties schema", schema: `{"additionalProperties":{"type":"number"}}`, output: `{"a":"x"}`, want: []string{"$.a: expected number, got string"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := ParseJSONSchema("test", []byte(tt.schema))
+			if err != nil {
+				t.Fatalf("ParseJSONSchema() error = %v", err)
+			}
+			if got := schema.Validate(tt.output).Violations; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONSchemaRejectsNonObjects(t *testing.T) {
+	for _, data := range []string{`[]`, `"string"`, `{not json`} {
+		if _, err := ParseJSONSchema("test", []byte(data)); err == nil {
+			t.Errorf("ParseJSONSchema(%s) error = nil, want an error", data)
+		}
+	}
+}
+
+func TestStripCodeFence(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{name: "plain", output: `{"a":1}`, want: `{"a":1}`},
+		{name: "surrounding space", output: "  {\"a\":1}\n", want: `{"a":1}`},
+		{name: "fence with language", output: "```json\n{\"a\":1}\n```", want: `{"a":1}`},
+		{name: "fence without language", output: "```\n[1]\n```\n", want: `[1]`},
+		{name: "unterminated fence", output: "```json\n{\"a\":1}", want: `{"a":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripCodeFence(tt.output); got != tt.want {
+				t.Errorf("StripCodeFence(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
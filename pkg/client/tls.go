@@ -0,0 +1,77 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSOptions configure connections to https servers
+type TLSOptions struct {
+	CAFile             string // PEM bundle trusted in addition to the system roots
+	CertFile           string // Client certificate for mutual TLS
+	KeyFile            string // Key of the client certificate
+	ServerName         string // Name checked against the server certificate instead of the host
+	InsecureSkipVerify bool   // Accept any server certificate
+}
+
+// IsZero reports whether no TLS option is set
+func (o TLSOptions) IsZero() bool {
+	return o == TLSOptions{}
+}
+
+// newTLSConfig loads the certificates named by the options. It returns nil
+// when no option is set, keeping the transport defaults.
+func newTLSConfig(options TLSOptions) (*tls.Config, error) {
+	if options.IsZero() {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         options.ServerName,
+		InsecureSkipVerify: options.InsecureSkipVerify,
+	}
+
+	if options.CAFile != "" {
+		pem, err := os.ReadFile(options.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", options.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if options.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// ValidateTLSOptions checks that the client certificate and key are given together
+func ValidateTLSOptions(options TLSOptions) error {
+	if (options.CertFile == "") != (options.KeyFile == "") {
+		value := options.CertFile
+		if value == "" {
+			value = options.KeyFile
+		}
+		return ValidationError{
+			Field:   "tls",
+			Value:   value,
+			Message: "the client certificate and key must be given together",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+var toolNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ToolHandler executes a tool call. arguments holds the raw JSON arguments
+// produced by the model and the returned string is sent back as the result.
+type ToolHandler func(ctx context.Context, arguments json.RawMessage) (string, error)
+
+// ToolDefinition describes a Go function the model may call
+type ToolDefinition struct {
+	Name        string
+	Description string
+	Parameters  map[string]interface{} // JSON Schema of the arguments object
+	Handler     ToolHandler
+}
+
+// ToolRegistry holds the tools offered to the model on every request
+type ToolRegistry struct {
+	mu    sync.RWMutex
+	tools map[string]ToolDefinition
+	order []string
+}
+
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{
+		tools: make(map[string]ToolDefinition),
+	}
+}
+
+// Register adds a tool, replacing any tool with the same name
+func (r *ToolRegistry) Register(def ToolDefinition) error {
+	if !toolNameRegex.MatchString(def.Name) {
+		return ValidationError{
+			Field:   "tool_name",
+			Value:   def.Name,
+			Message: "tool name must be 1-64 letters, digits, underscores or dashes",
+		}
+	}
+
+	if def.Handler == nil {
+		return ValidationError{
+			Field:   "tool_handler",
+			Value:   def.Name,
+			Message: "tool handler cannot be nil",
+		}
+	}
+
+	if def.Parameters == nil {
+		def.Parameters = map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.tools[def.Name]; !exists {
+		r.order = append(r.order, def.Name)
+	}
+	r.tools[def.Name] = def
+	return nil
+}
+
+// Unregister removes a tool by name
+func (r *ToolRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.tools[name]; !exists {
+		return
+	}
+	delete(r.tools, name)
+	for i, n := range r.order {
+		if n == name {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Len returns the number of registered tools
+func (r *ToolRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.tools)
+}
+
+// Tools returns the request representation of every registered tool
+func (r *ToolRegistry) Tools() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(r.order))
+	for _, name := range r.order {
+		def := r.tools[name]
+		tools = append(tools, Tool{
+			Type:        "function",
+			Name:        def.Name,
+			Description: def.Description,
+			Parameters:  def.Parameters,
+		})
+	}
+	return tools
+}
+
+// Execute runs the named tool with the JSON arguments produced by the model
+func (r *ToolRegistry) Execute(ctx context.Context, name string, arguments string) (string, error) {
+	r.mu.RLock()
+	def, exists := r.tools[name]
+	r.mu.RUnlock()
+
+	if !exists {
+		return "", fmt.Errorf("unknown tool: %s", name)
+	}
+
+	if arguments == "" {
+		arguments = "{}"
+	}
+	if !json.Valid([]byte(arguments)) {
+		return "", fmt.Errorf("invalid JSON arguments for tool %s", name)
+	}
+
+	return def.Handler(ctx, json.RawMessage(arguments))
+}
@@ -1,5 +1,16 @@
 package client
 
+import "encoding/json"
+
+// Content types passed to streaming callbacks
+const (
+	ContentTypeOutput    = "output"
+	ContentTypeReasoning = "reasoning"
+	ContentTypeTool      = "tool"
+	ContentTypeSummary   = "summary"
+	ContentTypeSources   = "sources"
+)
+
 // Status represents the status of a resource or operation
 type Status int
 
@@ -11,10 +22,34 @@ const (
 	StatusUnavailable
 )
 
-// Model represents an LM Studio model
+func (s Status) String() string {
+	switch s {
+	case StatusOn:
+		return "on"
+	case StatusOff:
+		return "off"
+	case StatusAvailable:
+		return "available"
+	case StatusUnavailable:
+		return "unavailable"
+	default:
+		return "unknown"
+	}
+}
+
+// Model represents an LM Studio model as reported by /api/v0/models
 type Model struct {
-	ID    string `json:"id"`
-	State string `json:"state"` // "loaded" or "not-loaded" or "loading"
+	ID                  string   `json:"id"`
+	Object              string   `json:"object"`
+	Type                string   `json:"type"` // "llm", "vlm" or "embeddings"
+	Publisher           string   `json:"publisher"`
+	Arch                string   `json:"arch"`
+	CompatibilityType   string   `json:"compatibility_type"` // "gguf" or "mlx"
+	Quantization        string   `json:"quantization"`
+	State               string   `json:"state"` // "loaded" or "not-loaded" or "loading"
+	MaxContextLength    int      `json:"max_context_length"`
+	LoadedContextLength int      `json:"loaded_context_length,omitempty"`
+	Capabilities        []string `json:"capabilities,omitempty"`
 }
 
 // ModelListResponse represents the response from the LM Studio API
@@ -28,17 +63,19 @@ type Quantization struct {
 }
 
 type LMSDownloadedListItem struct {
-	Type             string       `json:"type"`
-	ModelKey         string       `json:"modelKey"`
-	Format           string       `json:"format"`
-	DisplayName      string       `json:"displayName"`
-	Publisher        string       `json:"publisher"`
-	Path             string       `json:"path"`
-	SizeBytes        int64        `json:"sizeBytes"`
-	Architecture     string       `json:"architecture"`
-	Quantization     Quantization `json:"quantization"`
-	MaxContextLength int          `json:"maxContextLength"`
-	CanLoad          bool
+	Type             string         `json:"type"`
+	ModelKey         string         `json:"modelKey"`
+	Format           string         `json:"format"`
+	DisplayName      string         `json:"displayName"`
+	Publisher        string         `json:"publisher"`
+	Path             string         `json:"path"`
+	SizeBytes        int64          `json:"sizeBytes"`
+	Architecture     string         `json:"architecture"`
+	Quantization     Quantization   `json:"quantization"`
+	MaxContextLength int            `json:"maxContextLength"`
+	CanLoad          bool           `json:"-"` // Set from a load estimate, not by the server
+	Estimate         *ModelEstimate `json:"-"` // Last load estimate, nil until one is made
+	EstimateErr      error          `json:"-"` // Why the load estimate failed, if it did
 }
 
 type LMSLoadedListItem struct {
@@ -69,24 +106,77 @@ type ReasoningConfig struct {
 }
 
 type Tool struct {
-	Type string `json:"type"`
+	Type        string                 `json:"type"` // "function"
+	Name        string                 `json:"name,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
 }
 
+// Input item types
+const (
+	InputTypeMessage            = "message"
+	InputTypeFunctionCall       = "function_call"
+	InputTypeFunctionCallOutput = "function_call_output"
+)
+
+// InputMessage is an input item. Plain messages only set Role and Content,
+// function calls and their outputs set Type and the call fields instead.
 type InputMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Type      string         `json:"type,omitempty"`
+	Role      string         `json:"role,omitempty"`
+	Content   MessageContent `json:"content,omitempty"`
+	CallID    string         `json:"call_id,omitempty"`
+	Name      string         `json:"name,omitempty"`
+	Arguments string         `json:"arguments,omitempty"`
+	Output    string         `json:"output,omitempty"`
+}
+
+// SamplingParams are optional generation settings, nil fields leave the
+// server defaults in place
+type SamplingParams struct {
+	Temperature     *float64 `json:"temperature,omitempty"`
+	TopP            *float64 `json:"top_p,omitempty"`
+	MaxOutputTokens *int     `json:"max_output_tokens,omitempty"`
+	Stop            []string `json:"stop,omitempty"`
+	Seed            *int     `json:"seed,omitempty"`
+}
+
+// IsZero reports whether no parameter is set
+func (p SamplingParams) IsZero() bool {
+	return p.Temperature == nil && p.TopP == nil && p.MaxOutputTokens == nil && len(p.Stop) == 0 && p.Seed == nil
 }
 
 type ResponseRequest struct {
+	SamplingParams
 	Model              string           `json:"model"`
 	Input              []InputMessage   `json:"input"`
 	PreviousResponseID *string          `json:"previous_response_id,omitempty"`
 	Reasoning          *ReasoningConfig `json:"reasoning,omitempty"`
 	Tools              []Tool           `json:"tools,omitempty"`
+	Text               *TextConfig      `json:"text,omitempty"`
 	Stream             bool             `json:"stream,omitempty"`
 	Store              bool             `json:"store"`
+
+	// history is the full conversation behind a request that only carries
+	// the new turn, used when PreviousResponseID cannot be resolved
+	history []InputMessage
+}
+
+// TextConfig configures the format of the text output
+type TextConfig struct {
+	Format *TextFormat `json:"format,omitempty"`
 }
 
+// TextFormat asks for structured output following a JSON Schema
+type TextFormat struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name,omitempty"`
+	Schema json.RawMessage `json:"schema,omitempty"`
+	Strict bool            `json:"strict,omitempty"`
+}
+
+const TextFormatJSONSchema = "json_schema"
+
 type OutputContent struct {
 	Text string `json:"text,omitempty"`
 }
@@ -118,5 +208,39 @@ type ResponseOutputTextDeltaEvent struct {
 }
 
 type ResponseCompletedEvent struct {
-	ResponseID string `json:"response_id"`
+	ResponseID string            `json:"response_id"`
+	Response   CompletedResponse `json:"response"`
+}
+
+// CompletedResponse is the final response object sent with response.completed
+type CompletedResponse struct {
+	ID    string        `json:"id"`
+	Usage ResponseUsage `json:"usage"`
+}
+
+type ResponseUsage struct {
+	InputTokens         int `json:"input_tokens"`
+	OutputTokens        int `json:"output_tokens"`
+	OutputTokensDetails struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"output_tokens_details"`
+}
+
+// FunctionCallItem is a function_call output item
+type FunctionCallItem struct {
+	Type      string `json:"type"`
+	ID        string `json:"id"`
+	CallID    string `json:"call_id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type ResponseOutputItemEvent struct {
+	Item FunctionCallItem `json:"item"`
+}
+
+type ResponseFunctionCallArgumentsEvent struct {
+	ItemID    string `json:"item_id"`
+	Delta     string `json:"delta,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
 }
@@ -114,6 +114,114 @@ func ValidateScheme(scheme string) error {
 	}
 }
 
+func ValidateModelAPI(modelAPI string) error {
+	for _, valid := range ValidModelAPIs {
+		if modelAPI == valid {
+			return nil
+		}
+	}
+
+	return ValidationError{
+		Field:   "model_api",
+		Value:   modelAPI,
+		Message: fmt.Sprintf("model API must be one of: %v", ValidModelAPIs),
+	}
+}
+
+func ValidateChatAPI(chatAPI string) error {
+	for _, valid := range ValidChatAPIs {
+		if chatAPI == valid {
+			return nil
+		}
+	}
+
+	return ValidationError{
+		Field:   "chat_api",
+		Value:   chatAPI,
+		Message: fmt.Sprintf("chat API must be one of: %v", ValidChatAPIs),
+	}
+}
+
+func ValidateReasoningEffort(effort string) error {
+	for _, valid := range ValidReasoningEfforts {
+		if effort == valid {
+			return nil
+		}
+	}
+
+	return ValidationError{
+		Field:   "reasoning_effort",
+		Value:   effort,
+		Message: fmt.Sprintf("reasoning effort must be one of: %v", ValidReasoningEfforts),
+	}
+}
+
+func ValidateSamplingParams(params SamplingParams) error {
+	if params.Temperature != nil && (*params.Temperature < 0 || *params.Temperature > MaxTemperature) {
+		return ValidationError{
+			Field:   "temperature",
+			Value:   strconv.FormatFloat(*params.Temperature, 'g', -1, 64),
+			Message: fmt.Sprintf("temperature must be between 0 and %g", MaxTemperature),
+		}
+	}
+
+	if params.TopP != nil && (*params.TopP <= 0 || *params.TopP > 1) {
+		return ValidationError{
+			Field:   "top_p",
+			Value:   strconv.FormatFloat(*params.TopP, 'g', -1, 64),
+			Message: "top_p must be greater than 0 and at most 1",
+		}
+	}
+
+	if params.MaxOutputTokens != nil && *params.MaxOutputTokens <= 0 {
+		return ValidationError{
+			Field:   "max_output_tokens",
+			Value:   strconv.Itoa(*params.MaxOutputTokens),
+			Message: "max output tokens must be positive",
+		}
+	}
+
+	if len(params.Stop) > MaxStopSequences {
+		return ValidationError{
+			Field:   "stop",
+			Value:   strings.Join(params.Stop, ","),
+			Message: fmt.Sprintf("at most %d stop sequences are allowed", MaxStopSequences),
+		}
+	}
+
+	for _, stop := range params.Stop {
+		if stop == "" {
+			return ValidationError{
+				Field:   "stop",
+				Value:   stop,
+				Message: "stop sequences cannot be empty",
+			}
+		}
+	}
+
+	return nil
+}
+
+func ValidatePresetName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return ValidationError{
+			Field:   "preset_name",
+			Value:   name,
+			Message: "preset name cannot be empty",
+		}
+	}
+
+	if len(name) > MaxModelIDLength {
+		return ValidationError{
+			Field:   "preset_name",
+			Value:   name,
+			Message: fmt.Sprintf("preset name exceeds maximum length of %d characters", MaxModelIDLength),
+		}
+	}
+
+	return nil
+}
+
 func ValidateURL(urlStr string) error {
 	if strings.TrimSpace(urlStr) == "" {
 		return ValidationError{
@@ -238,6 +346,24 @@ func ValidateClientConfig(config ClientConfig) error {
 		return err
 	}
 
+	if err := ValidateModelAPI(config.ModelAPI); err != nil {
+		return err
+	}
+
+	if err := ValidateChatAPI(config.ChatAPI); err != nil {
+		return err
+	}
+
+	if err := ValidateReasoningEffort(config.ReasoningEffort); err != nil {
+		return err
+	}
+
+	if config.Retrieval {
+		if err := ValidateModelID(config.EmbeddingModel); err != nil {
+			return fmt.Errorf("invalid embedding model: %w", err)
+		}
+	}
+
 	if config.HTTPTimeout <= 0 {
 		return ValidationError{
 			Field:   "http_timeout",
@@ -262,6 +388,42 @@ func ValidateClientConfig(config ClientConfig) error {
 		}
 	}
 
+	if err := ValidateTLSOptions(config.TLS); err != nil {
+		return err
+	}
+
+	if err := ValidateHeaders(config.Headers); err != nil {
+		return err
+	}
+
+	if strings.ContainsAny(config.APIKey, "\r\n") {
+		return ValidationError{
+			Field:   "api_key",
+			Value:   redactedValue,
+			Message: "API key cannot contain line breaks",
+		}
+	}
+
+	if config.DefaultModel != "" {
+		if err := ValidateModelID(config.DefaultModel); err != nil {
+			return fmt.Errorf("invalid default model: %w", err)
+		}
+	}
+
+	if config.SystemPrompt != "" {
+		if err := ValidateSystemMessage(config.SystemPrompt); err != nil {
+			return fmt.Errorf("invalid system prompt: %w", err)
+		}
+	}
+
+	if config.UI.MaxLogLines < 0 {
+		return ValidationError{
+			Field:   "ui.max_log_lines",
+			Value:   strconv.Itoa(config.UI.MaxLogLines),
+			Message: "max log lines cannot be negative",
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,52 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+)
+
+// AliasLoaded is an alias target that resolves to the first loaded model
+const AliasLoaded = "@loaded"
+
+// ParseAliases parses alias=model pairs such as gpt-4o=qwen/qwen3-8b or
+// gpt-4o=@loaded
+func ParseAliases(pairs []string) (map[string]string, error) {
+	aliases := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		alias, target, ok := strings.Cut(pair, "=")
+		alias, target = strings.TrimSpace(alias), strings.TrimSpace(target)
+		if !ok || alias == "" || target == "" {
+			return nil, fmt.Errorf("invalid alias %q, expected alias=model", pair)
+		}
+		if target != AliasLoaded {
+			if err := client.ValidateModelID(target); err != nil {
+				return nil, fmt.Errorf("invalid alias %q: %w", pair, err)
+			}
+		}
+		if _, exists := aliases[alias]; exists {
+			return nil, fmt.Errorf("alias %q is defined twice", alias)
+		}
+		aliases[alias] = target
+	}
+	return aliases, nil
+}
+
+// resolveModel maps a requested model name to the model sent to the server.
+// Names without an alias are passed through.
+func (s *Server) resolveModel(model string) (string, error) {
+	target, ok := s.aliases[model]
+	if !ok {
+		return model, nil
+	}
+	if target != AliasLoaded {
+		return target, nil
+	}
+
+	loaded, err := s.client.GetActiveModel()
+	if err != nil {
+		return "", fmt.Errorf("alias %s: %w", model, err)
+	}
+	return loaded, nil
+}
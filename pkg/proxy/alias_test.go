@@ -0,0 +1,39 @@
+package proxy
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		pairs   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "none", pairs: nil, want: map[string]string{}},
+		{
+			name:  "model and loaded",
+			pairs: []string{"gpt-4o=@loaded", " gpt-4o-mini = qwen/qwen3-8b "},
+			want:  map[string]string{"gpt-4o": AliasLoaded, "gpt-4o-mini": "qwen/qwen3-8b"},
+		},
+		{name: "target with equals sign", pairs: []string{"a=b=c"}, want: map[string]string{"a": "b=c"}},
+		{name: "missing target", pairs: []string{"gpt-4o="}, wantErr: true},
+		{name: "missing alias", pairs: []string{"=qwen/qwen3-8b"}, wantErr: true},
+		{name: "no equals sign", pairs: []string{"gpt-4o"}, wantErr: true},
+		{name: "defined twice", pairs: []string{"gpt-4o=a", "gpt-4o=b"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAliases(tt.pairs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAliases() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("ParseAliases() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
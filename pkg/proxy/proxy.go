@@ -0,0 +1,261 @@
+// Package proxy serves an OpenAI-compatible API that forwards to LM Studio,
+// renaming models through aliases and recording the traffic.
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+)
+
+const (
+	DefaultListenAddress = "127.0.0.1:8080"
+	MaxRequestBodySize   = 64 << 20 // 64 MB, images are sent inline
+	copyBufferSize       = 4096
+)
+
+// Options configure a proxy server
+type Options struct {
+	// Aliases maps model names used by callers to local models or AliasLoaded
+	Aliases map[string]string
+	// Recorder receives every request and response when set
+	Recorder *Recorder
+}
+
+// Server forwards OpenAI-compatible requests through a client
+type Server struct {
+	client   *client.Client
+	logger   *client.Logger
+	aliases  map[string]string
+	recorder *Recorder
+}
+
+func NewServer(lmsClient *client.Client, opts Options) *Server {
+	return &Server{
+		client:   lmsClient,
+		logger:   lmsClient.GetLogger(),
+		aliases:  opts.Aliases,
+		recorder: opts.Recorder,
+	}
+}
+
+// Handler returns the HTTP handler of the proxied endpoints
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/chat/completions", s.handleModelRequest)
+	mux.HandleFunc("POST /v1/responses", s.handleModelRequest)
+	mux.HandleFunc("GET /v1/models", s.handleModels)
+	return mux
+}
+
+// handleModelRequest rewrites the model of a chat request and streams the
+// server response back
+func (s *Server) handleModelRequest(w http.ResponseWriter, r *http.Request) {
+	record := Record{Time: time.Now(), Method: r.Method, Path: r.URL.Path}
+	defer s.record(&record)
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxRequestBodySize))
+	if err != nil {
+		s.fail(w, &record, http.StatusRequestEntityTooLarge, fmt.Errorf("failed to read request: %w", err))
+		return
+	}
+	record.Request = rawBody(body)
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		s.fail(w, &record, http.StatusBadRequest, fmt.Errorf("request is not a JSON object: %w", err))
+		return
+	}
+	if err := json.Unmarshal(fields["model"], &record.Model); err != nil {
+		s.fail(w, &record, http.StatusBadRequest, fmt.Errorf("request has no model"))
+		return
+	}
+
+	record.Forwarded, err = s.resolveModel(record.Model)
+	if err != nil {
+		s.fail(w, &record, http.StatusServiceUnavailable, err)
+		return
+	}
+	if record.Forwarded != record.Model {
+		fields["model"], _ = json.Marshal(record.Forwarded)
+		if body, err = json.Marshal(fields); err != nil {
+			s.fail(w, &record, http.StatusInternalServerError, fmt.Errorf("failed to encode request: %w", err))
+			return
+		}
+	}
+
+	resp, err := s.client.Forward(r.Context(), r.Method, r.URL.Path, bytes.NewReader(body), r.Header)
+	if err != nil {
+		s.fail(w, &record, http.StatusBadGateway, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	record.Status = resp.StatusCode
+	copyHeaders(w.Header(), resp.Header)
+	w.WriteHeader(resp.StatusCode)
+
+	var captured bytes.Buffer
+	if err := streamBody(w, io.TeeReader(resp.Body, &captured)); err != nil {
+		record.Error = err.Error()
+	}
+	record.Response = rawBody(captured.Bytes())
+}
+
+// handleModels lists the server models followed by the aliases, so callers
+// that check model names before using them accept the aliases
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	record := Record{Time: time.Now(), Method: r.Method, Path: r.URL.Path}
+	defer s.record(&record)
+
+	resp, err := s.client.Forward(r.Context(), r.Method, r.URL.Path, nil, r.Header)
+	if err != nil {
+		s.fail(w, &record, http.StatusBadGateway, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.fail(w, &record, http.StatusBadGateway, fmt.Errorf("failed to read response: %w", err))
+		return
+	}
+	if resp.StatusCode == http.StatusOK {
+		body = s.withAliases(body)
+	}
+
+	record.Status = resp.StatusCode
+	record.Response = rawBody(body)
+	copyHeaders(w.Header(), resp.Header)
+	w.WriteHeader(resp.StatusCode)
+	w.Write(body)
+}
+
+// withAliases appends the aliases to a model list, leaving bodies it does
+// not understand unchanged
+func (s *Server) withAliases(body []byte) []byte {
+	if len(s.aliases) == 0 {
+		return body
+	}
+
+	var list struct {
+		Object string            `json:"object"`
+		Data   []json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return body
+	}
+
+	aliases := make([]string, 0, len(s.aliases))
+	for alias := range s.aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	for _, alias := range aliases {
+		entry, _ := json.Marshal(map[string]string{
+			"id":       alias,
+			"object":   "model",
+			"owned_by": "lazylms",
+		})
+		list.Data = append(list.Data, entry)
+	}
+
+	if list.Object == "" {
+		list.Object = "list"
+	}
+	extended, err := json.Marshal(list)
+	if err != nil {
+		return body
+	}
+	return extended
+}
+
+// fail answers with an OpenAI-style error body
+func (s *Server) fail(w http.ResponseWriter, record *Record, status int, err error) {
+	record.Status = status
+	record.Error = err.Error()
+
+	body, _ := json.Marshal(map[string]any{
+		"error": map[string]string{
+			"message": err.Error(),
+			"type":    "proxy_error",
+		},
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// record logs a finished request and appends it to the record file
+func (s *Server) record(record *Record) {
+	record.DurationMs = time.Since(record.Time).Milliseconds()
+
+	request := record.Method + " " + record.Path
+	if record.Model != "" {
+		request += " " + record.Model
+	}
+	if record.Forwarded != "" && record.Forwarded != record.Model {
+		request += " -> " + record.Forwarded
+	}
+	if record.Error != "" {
+		s.logger.Warn("%s: %d %s", request, record.Status, record.Error)
+	} else {
+		s.logger.Info("%s: %d in %dms", request, record.Status, record.DurationMs)
+	}
+
+	if s.recorder == nil {
+		return
+	}
+	if err := s.recorder.Write(*record); err != nil {
+		s.logger.Error("Failed to record request: %v", err)
+	}
+}
+
+// hopHeaders are connection-specific and not copied from the server response
+var hopHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Length":    true,
+	"Keep-Alive":        true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+}
+
+func copyHeaders(dst, src http.Header) {
+	for name, values := range src {
+		if hopHeaders[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		for _, value := range values {
+			dst.Add(name, value)
+		}
+	}
+}
+
+// streamBody copies a response body, flushing after every read so streamed
+// events reach the caller as they arrive
+func streamBody(w http.ResponseWriter, body io.Reader) error {
+	controller := http.NewResponseController(w)
+	buf := make([]byte, copyBufferSize)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
+				return fmt.Errorf("write response: %w", writeErr)
+			}
+			controller.Flush()
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read response: %w", err)
+		}
+	}
+}
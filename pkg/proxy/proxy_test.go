@@ -0,0 +1,298 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+)
+
+// testBackend forwards to an upstream test server and reports fixed loaded
+// models. The other Backend methods are never called by the proxy.
+type testBackend struct {
+	client.Backend
+	upstream string
+	loaded   []client.LMSLoadedListItem
+}
+
+func (b *testBackend) Name() string { return "test" }
+
+func (b *testBackend) GetLoadedModels() ([]client.LMSLoadedListItem, error) {
+	return b.loaded, nil
+}
+
+func (b *testBackend) Forward(ctx context.Context, method, path string, body io.Reader, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, b.upstream+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", header.Get("Content-Type"))
+	return http.DefaultClient.Do(req)
+}
+
+// upstream is a fake LM Studio that remembers the request bodies it received
+type upstream struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (u *upstream) received() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.bodies
+}
+
+// newTestProxy starts an upstream server answering with handler and a proxy
+// in front of it
+func newTestProxy(t *testing.T, handler http.HandlerFunc, loaded []client.LMSLoadedListItem, opts Options) (*httptest.Server, *upstream) {
+	t.Helper()
+	received := &upstream{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if len(body) > 0 {
+			received.mu.Lock()
+			received.bodies = append(received.bodies, string(body))
+			received.mu.Unlock()
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	backend := &testBackend{upstream: server.URL, loaded: loaded}
+	lmsClient, err := client.NewClientWithBackend(context.Background(), client.DefaultClientConfig(), backend, nil)
+	if err != nil {
+		t.Fatalf("NewClientWithBackend() error = %v", err)
+	}
+	proxy := httptest.NewServer(NewServer(lmsClient, opts).Handler())
+	t.Cleanup(proxy.Close)
+	return proxy, received
+}
+
+// post sends a JSON request and returns the response with its body
+func post(t *testing.T, url, body string) (*http.Response, string) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST %s error = %v", url, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading the response error = %v", err)
+	}
+	return resp, string(data)
+}
+
+func answerJSON(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}
+}
+
+func mustMarshal(t *testing.T, value any) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestProxyRewritesModels(t *testing.T) {
+	aliases := map[string]string{"gpt-4o": AliasLoaded, "gpt-4o-mini": "qwen/qwen3-8b"}
+	gemma := []client.LMSLoadedListItem{{Identifier: "google/gemma-3-4b"}}
+	// Keys in order and without spaces, as the proxy encodes a rewritten request
+	request := func(model string) string {
+		return `{"input":[{"content":"hi","role":"user"}],"model":"` + model + `","temperature":0.5}`
+	}
+
+	tests := []struct {
+		name       string
+		model      string
+		loaded     []client.LMSLoadedListItem
+		wantStatus int
+		want       []string // Requests the server received
+	}{
+		{name: "alias of a model", model: "gpt-4o-mini", loaded: gemma, wantStatus: http.StatusOK, want: []string{request("qwen/qwen3-8b")}},
+		{name: "alias of the loaded model", model: "gpt-4o", loaded: gemma, wantStatus: http.StatusOK, want: []string{request("google/gemma-3-4b")}},
+		{name: "no alias", model: "qwen/qwen3-32b", loaded: gemma, wantStatus: http.StatusOK, want: []string{request("qwen/qwen3-32b")}},
+		{name: "nothing loaded", model: "gpt-4o", wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy, upstream := newTestProxy(t, answerJSON(`{"id":"resp_1"}`), tt.loaded, Options{Aliases: aliases})
+
+			resp, body := post(t, proxy.URL+"/v1/responses", request(tt.model))
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus == http.StatusOK && body != `{"id":"resp_1"}` {
+				t.Errorf("response = %s, want the server response", body)
+			}
+			if got := upstream.received(); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("server received %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProxyStreamsEvents(t *testing.T) {
+	events := []string{
+		"event: response.output_text.delta\ndata: {\"delta\":\"Hel\"}\n\n",
+		"event: response.output_text.delta\ndata: {\"delta\":\"lo\"}\n\n",
+		"data: [DONE]\n\n",
+	}
+	// The next event is only sent once the previous one reached the caller,
+	// so the test hangs unless every event is flushed on its own
+	next := make(chan struct{})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for i, event := range events {
+			if i > 0 {
+				<-next
+			}
+			io.WriteString(w, event)
+			w.(http.Flusher).Flush()
+		}
+	}
+	proxy, _ := newTestProxy(t, handler, nil, Options{})
+
+	resp, err := http.Post(proxy.URL+"/v1/responses", "application/json", strings.NewReader(`{"model":"qwen/qwen3-8b","stream":true}`))
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var streamed strings.Builder
+	for i, event := range events {
+		for range strings.Count(event, "\n") {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("reading event %d error = %v", i, err)
+			}
+			streamed.WriteString(line)
+		}
+		if i < len(events)-1 {
+			next <- struct{}{}
+		}
+	}
+	if want := strings.Join(events, ""); streamed.String() != want {
+		t.Errorf("streamed = %q, want %q", streamed.String(), want)
+	}
+}
+
+func TestProxyRecordsExchanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.jsonl")
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/chat/completions" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error":"no such model"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: {\"delta\":\"hi\"}\n\n")
+	}
+	loaded := []client.LMSLoadedListItem{{Identifier: "google/gemma-3-4b"}}
+	proxy, _ := newTestProxy(t, handler, loaded, Options{Aliases: map[string]string{"gpt-4o": AliasLoaded}, Recorder: recorder})
+
+	post(t, proxy.URL+"/v1/responses", `{"model":"gpt-4o","stream":true}`)
+	post(t, proxy.URL+"/v1/chat/completions", `{"model":"missing"}`)
+	post(t, proxy.URL+"/v1/chat/completions", `not json`)
+	proxy.Close() // Waits for the handlers, and so for their records
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("recorded %d lines, want 3:\n%s", len(lines), data)
+	}
+
+	want := []Record{
+		{
+			Method: "POST", Path: "/v1/responses", Model: "gpt-4o", Forwarded: "google/gemma-3-4b", Status: http.StatusOK,
+			Request:  json.RawMessage(`{"model":"gpt-4o","stream":true}`),
+			Response: json.RawMessage(`"data: {\"delta\":\"hi\"}\n\n"`),
+		},
+		{
+			Method: "POST", Path: "/v1/chat/completions", Model: "missing", Forwarded: "missing", Status: http.StatusNotFound,
+			Request:  json.RawMessage(`{"model":"missing"}`),
+			Response: json.RawMessage(`{"error":"no such model"}`),
+		},
+		{
+			Method: "POST", Path: "/v1/chat/completions", Status: http.StatusBadRequest,
+			Request: json.RawMessage(`"not json"`),
+			Error:   "request is not a JSON object: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+	for i, line := range lines {
+		var got Record
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d is not a record: %v", i+1, err)
+		}
+		if got.Time.IsZero() {
+			t.Errorf("line %d has no time", i+1)
+		}
+		got.Time, got.DurationMs = want[i].Time, 0
+		if mustMarshal(t, got) != mustMarshal(t, want[i]) {
+			t.Errorf("line %d = %s, want %s", i+1, mustMarshal(t, got), mustMarshal(t, want[i]))
+		}
+	}
+}
+
+func TestProxyListsAliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstream string
+		want     string
+	}{
+		{
+			name:     "aliases appended",
+			upstream: `{"object":"list","data":[{"id":"qwen/qwen3-8b","object":"model"}]}`,
+			want:     `{"object":"list","data":[{"id":"qwen/qwen3-8b","object":"model"},{"id":"gpt-4o","object":"model","owned_by":"lazylms"},{"id":"gpt-4o-mini","object":"model","owned_by":"lazylms"}]}`,
+		},
+		{name: "unknown body kept", upstream: `[1,2]`, want: `[1,2]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy, _ := newTestProxy(t, answerJSON(tt.upstream), nil, Options{Aliases: map[string]string{"gpt-4o-mini": "qwen/qwen3-8b", "gpt-4o": AliasLoaded}})
+
+			resp, err := http.Get(proxy.URL + "/v1/models")
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.want {
+				t.Errorf("models = %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
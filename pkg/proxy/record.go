@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Record is one proxied request and its response, written as a JSONL line
+type Record struct {
+	Time       time.Time       `json:"time"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	Model      string          `json:"model,omitempty"`           // Model named by the caller
+	Forwarded  string          `json:"forwarded_model,omitempty"` // Model sent to the server after aliasing
+	Status     int             `json:"status"`
+	DurationMs int64           `json:"duration_ms"`
+	Request    json.RawMessage `json:"request,omitempty"`
+	// Response is the JSON body, or the raw text of a streamed response
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// Recorder appends records to a JSONL file
+type Recorder struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+// NewRecorder opens path for appending, creating it when needed
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open record file: %w", err)
+	}
+	return &Recorder{file: file}, nil
+}
+
+// Write appends a record as a single line
+func (r *Recorder) Write(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the record file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// rawBody keeps a JSON body as it is and turns anything else, such as a
+// server-sent event stream, into a JSON string
+func rawBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return json.RawMessage(body)
+	}
+	text, _ := json.Marshal(string(body))
+	return text
+}
@@ -1,9 +1,17 @@
 package tui
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/Rugz007/lazylms/pkg/client"
+
 	"github.com/Rugz007/lazylms/pkg/tui/layout"
+	"github.com/Rugz007/lazylms/pkg/tui/rendering"
 	"github.com/Rugz007/lazylms/pkg/tui/styles"
 )
 
@@ -19,7 +27,20 @@ func (m Model) renderChatView(mainHeight, rightColumnWidth int) string {
 	}
 
 	embeddedText := map[layout.BorderPosition]string{
-		layout.TopLeftBorder: "[4] ◆ Chat",
+		layout.TopLeftBorder:  "[4] ◆ Chat",
+		layout.TopRightBorder: "reasoning: " + m.client.ReasoningEffort(),
+	}
+	if m.client.RetrievalEnabled() {
+		embeddedText[layout.TopRightBorder] += " · rag"
+	}
+	if schema := m.client.Schema(); schema != nil {
+		embeddedText[layout.TopRightBorder] += " · schema: " + schema.Name
+	}
+	if gauge := m.renderContextGauge(); gauge != "" {
+		embeddedText[layout.BottomLeftBorder] = gauge
+	}
+	if metrics := m.lastMessageMetrics(); metrics != nil {
+		embeddedText[layout.BottomRightBorder] = metrics.String()
 	}
 
 	content = lipgloss.NewStyle().Padding(1).Render(content)
@@ -48,6 +69,191 @@ func (m Model) renderChatInputView(rightColumnWidth int) string {
 	embeddedText := map[layout.BorderPosition]string{
 		layout.TopLeftBorder: "Input",
 	}
+	if len(m.pendingImages) > 0 {
+		embeddedText[layout.TopRightBorder] = fmt.Sprintf("📎 %d image(s)", len(m.pendingImages))
+	}
 
 	return layout.Borderize(content, active, rightColumnWidth-2, 3, embeddedText)
 }
+
+// cycleReasoningEffort moves to the next reasoning effort level and
+// remembers it for the selected model
+func (m *Model) cycleReasoningEffort() tea.Cmd {
+	efforts := client.ValidReasoningEfforts
+	current := slices.Index(efforts, m.client.ReasoningEffort())
+	next := efforts[(current+1)%len(efforts)]
+
+	if err := m.client.SetReasoningEffort(next); err != nil {
+		return func() tea.Msg { return logMsg(fmt.Sprintf("Failed to set reasoning effort: %v", err)) }
+	}
+
+	if m.selectedModel == "" || m.preferences == nil {
+		return nil
+	}
+
+	m.preferences.SetReasoningEffort(m.selectedModel, next)
+	preferences := m.preferences
+	return func() tea.Msg {
+		if err := preferences.Save(); err != nil {
+			return logMsg(fmt.Sprintf("Failed to save preferences: %v", err))
+		}
+		return nil
+	}
+}
+
+// applyModelPreferences restores the remembered settings of the selected model
+func (m *Model) applyModelPreferences() {
+	if m.selectedModel == "" || m.preferences == nil {
+		return
+	}
+	if effort, ok := m.preferences.ReasoningEffortFor(m.selectedModel); ok {
+		m.client.SetReasoningEffort(effort)
+	}
+}
+
+// lastMessageMetrics returns the metrics of the most recent AI message
+func (m Model) lastMessageMetrics() *rendering.MessageMetrics {
+	for i := len(m.chatMessages) - 1; i >= 0; i-- {
+		if m.chatMessages[i].Metrics != nil {
+			return m.chatMessages[i].Metrics
+		}
+	}
+	return nil
+}
+
+// messageMetrics converts client metrics for storage on a chat message
+func messageMetrics(metrics client.StreamMetrics) *rendering.MessageMetrics {
+	if metrics.OutputTokens == 0 && metrics.InputTokens == 0 {
+		return nil
+	}
+	return &rendering.MessageMetrics{
+		InputTokens:      metrics.InputTokens,
+		OutputTokens:     metrics.OutputTokens,
+		ReasoningTokens:  metrics.ReasoningTokens,
+		TimeToFirstToken: metrics.TimeToFirstToken,
+		TokensPerSecond:  metrics.TokensPerSecond(),
+	}
+}
+
+// selectedLoadedModel returns the loaded model selected for chat
+func (m Model) selectedLoadedModel() (client.LMSLoadedListItem, bool) {
+	for _, model := range m.loadedModels {
+		if model.Identifier == m.selectedModel {
+			return model, true
+		}
+	}
+	return client.LMSLoadedListItem{}, false
+}
+
+// selectedContextLength returns the context window of the selected model
+func (m Model) selectedContextLength() int {
+	if model, ok := m.selectedLoadedModel(); ok {
+		return client.ModelContextLength(model)
+	}
+	return 0
+}
+
+// renderContextGauge shows how much of the selected model's context window
+// the conversation uses, e.g. "ctx ███░░░░░░░ 1.2k/4.1k"
+func (m Model) renderContextGauge() string {
+	contextLength := m.selectedContextLength()
+	if contextLength == 0 {
+		return ""
+	}
+
+	ratio := min(float64(m.contextTokens)/float64(contextLength), 1)
+	filled := int(ratio * ContextGaugeWidth)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", ContextGaugeWidth-filled)
+
+	gauge := fmt.Sprintf("ctx %s %s/%s", bar, formatTokenCount(m.contextTokens), formatTokenCount(contextLength))
+	if ratio >= ContextWarningRatio {
+		gauge = "⚠ " + gauge
+	}
+	return gauge
+}
+
+// formatTokenCount shortens large token counts, e.g. 12345 becomes "12.3k"
+func formatTokenCount(tokens int) string {
+	if tokens < 1000 {
+		return fmt.Sprintf("%d", tokens)
+	}
+	return fmt.Sprintf("%.1fk", float64(tokens)/1000)
+}
+
+// insertSummary shows a compaction summary before the message being answered
+func (m *Model) insertSummary(summary string) {
+	summaryMsg := rendering.ChatMessage{
+		Type:    rendering.MessageTypeSummary,
+		Author:  m.selectedModel,
+		Content: summary,
+	}
+
+	// The user message that triggered compaction stays below the summary
+	at := len(m.chatMessages)
+	if at > 0 && m.chatMessages[at-1].Type == rendering.MessageTypeUser {
+		at--
+	}
+	m.chatMessages = slices.Insert(m.chatMessages, at, summaryMsg)
+	m.refreshChatViewport()
+}
+
+// toggleSummaries expands or collapses every summary message
+func (m *Model) toggleSummaries() {
+	expanded := false
+	for _, msg := range m.chatMessages {
+		if msg.Type == rendering.MessageTypeSummary {
+			expanded = !msg.Expanded
+			break
+		}
+	}
+	for i := range m.chatMessages {
+		if m.chatMessages[i].Type == rendering.MessageTypeSummary {
+			m.chatMessages[i].Expanded = expanded
+		}
+	}
+	m.refreshChatViewport()
+}
+
+// refreshChatViewport re-renders the stored chat messages
+func (m *Model) refreshChatViewport() {
+	var renderedMessages []string
+	for _, msg := range m.chatMessages {
+		renderedMessages = append(renderedMessages, rendering.RenderChatMessage(msg, m.chatViewport.Width, m.logChan))
+	}
+	m.chatViewport.SetContent(strings.Join(renderedMessages, "\n"))
+}
+
+// structuredSegments replaces the output of a structured response with
+// pretty-printed JSON when it parses
+func structuredSegments(segments []rendering.ContentSegment) []rendering.ContentSegment {
+	var output strings.Builder
+	for _, seg := range segments {
+		if seg.Type == rendering.ContentTypeOutput {
+			output.WriteString(seg.Text)
+		}
+	}
+
+	pretty, ok := rendering.PrettyJSON(output.String())
+	if !ok {
+		return segments
+	}
+
+	structured := make([]rendering.ContentSegment, 0, len(segments))
+	for _, seg := range segments {
+		if seg.Type != rendering.ContentTypeOutput {
+			structured = append(structured, seg)
+		}
+	}
+	return append(structured, rendering.ContentSegment{Text: pretty, Type: rendering.ContentTypeJSON})
+}
+
+// schemaStatus converts a schema validation for display on a chat message
+func schemaStatus(validation *client.SchemaValidation) *rendering.SchemaStatus {
+	if validation == nil {
+		return nil
+	}
+	return &rendering.SchemaStatus{
+		Name:       validation.Schema,
+		Violations: len(validation.Violations),
+	}
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/Rugz007/lazylms/pkg/client"
@@ -68,16 +69,18 @@ func (m Model) updateModelsCmd() tea.Cmd {
 			downloaded = []client.LMSDownloadedListItem{}
 		}
 
-		// Preserve existing CanLoad status from current models
-		existingCanLoad := make(map[string]bool)
+		// Preserve existing estimates from current models
+		existing := make(map[string]client.LMSDownloadedListItem)
 		for _, model := range m.downloadedModels {
-			existingCanLoad[model.ModelKey] = model.CanLoad
+			existing[model.ModelKey] = model
 		}
 
-		// Apply existing CanLoad status to new list
+		// Apply existing estimates to new list
 		for i, model := range downloaded {
-			if canLoad, exists := existingCanLoad[model.ModelKey]; exists {
-				downloaded[i].CanLoad = canLoad
+			if previous, exists := existing[model.ModelKey]; exists {
+				downloaded[i].CanLoad = previous.CanLoad
+				downloaded[i].Estimate = previous.Estimate
+				downloaded[i].EstimateErr = previous.EstimateErr
 			}
 		}
 
@@ -118,17 +121,34 @@ func (m Model) shouldRunEstimates(loadedModels []client.LMSLoadedListItem) bool
 	return false
 }
 
-func (m Model) updateEstimatesCmd() tea.Cmd {
+// runEstimates starts estimating the downloaded models. While a run is in
+// progress another one is queued instead, so lms is never flooded.
+func (m *Model) runEstimates() tea.Cmd {
+	if m.client.CanManageModels() != nil {
+		return nil
+	}
+	if m.estimating {
+		m.estimatesQueued = true
+		return nil
+	}
+	m.estimating = true
+	m.estimateFailures, m.estimateErr = 0, nil
+
+	lmsClient := m.client
+	models := slices.Clone(m.downloadedModels)
 	return func() tea.Msg {
-		downloaded, err := m.client.GetDownloadedModels()
-		if err != nil {
-			if m.client != nil && m.client.GetLogger() != nil {
-				m.client.GetLogger().Error("Failed to get downloaded models with estimates: %v", err)
-			}
-			downloaded = []client.LMSDownloadedListItem{}
-		}
+		return waitForEstimate(lmsClient.EstimateModels(models))()
+	}
+}
 
-		return estimatesMsg(downloaded)
+// waitForEstimate reads the next estimate of a run
+func waitForEstimate(results <-chan client.EstimateResult) tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-results
+		if !ok {
+			return estimatesDoneMsg{}
+		}
+		return estimatesMsg{result: result, results: results}
 	}
 }
 
@@ -148,7 +168,9 @@ func (m Model) logListenerCmd() tea.Cmd {
 	})
 }
 
-func (m Model) handleDownloadedModelSelection() tea.Cmd {
+// handleDownloadedModelSelection opens the load options of the selected
+// downloaded model
+func (m *Model) handleDownloadedModelSelection() tea.Cmd {
 	if m.downloadedList.Items() == nil || len(m.downloadedList.Items()) == 0 {
 		return nil
 	}
@@ -172,13 +194,28 @@ func (m Model) handleDownloadedModelSelection() tea.Cmd {
 		}
 		return nil
 	}
-	m.client.GetLogger().Info("Loading model: %s", modelItem.model.ModelKey)
-	return m.loadModelCmd(modelItem.model.ModelKey)
+	if cmd := m.requireModelManagement(); cmd != nil {
+		return cmd
+	}
+	m.openLoadPopup(modelItem.model)
+	return nil
+}
+
+// requireModelManagement returns a command logging why models cannot be
+// loaded, unloaded or downloaded on the current server, or nil when they can
+func (m Model) requireModelManagement() tea.Cmd {
+	err := m.client.CanManageModels()
+	if err == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return logMsg(fmt.Sprintf("Unavailable on this server: %v", err))
+	}
 }
 
-func (m Model) loadModelCmd(modelID string) tea.Cmd {
+func (m Model) loadModelCmd(modelID string, options client.LoadOptions) tea.Cmd {
 	return func() tea.Msg {
-		err := m.client.LoadModel(modelID)
+		err := m.client.LoadModel(modelID, options)
 		if err != nil {
 			return logMsg(fmt.Sprintf("Failed to load model %s: %v", modelID, err))
 		}
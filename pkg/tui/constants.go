@@ -10,6 +10,11 @@ const (
 	// UI-specific constants that don't belong in client config
 	ChatInputCharLimit   = 500
 	SystemInputCharLimit = 1000
+	DownloadInputLimit   = 200
+
+	// Context gauge in the chat border
+	ContextGaugeWidth   = 10
+	ContextWarningRatio = 0.9
 
 	// UI refresh intervals
 	LogCheckInterval = 100 * time.Millisecond
@@ -17,7 +22,6 @@ const (
 
 // Use client configuration constants for shared values
 var (
-	MaxLogLines             = client.DefaultMaxLogLines
 	StreamChannelBufferSize = client.DefaultStreamChannelSize
 	EstimateTickInterval    = client.DefaultEstimateInterval
 	AnimationTickInterval   = client.DefaultAnimationInterval
@@ -27,6 +31,7 @@ var (
 const (
 	ChatInputPlaceholder     = "Type your message and press Enter..."
 	SystemInputPlaceholder   = "Enter system prompt..."
+	DownloadInputPlaceholder = "qwen/qwen3-8b or a search term"
 	NoModelsPlaceholder      = "No models loaded - load a model first"
 	SelectModelPlaceholder   = "Select a model first (press Enter on loaded model)"
 	ChatWithModelPlaceholder = "Chat with %s (Enter to send, arrows to scroll)"
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+	"github.com/Rugz007/lazylms/pkg/tui/layout"
+	"github.com/Rugz007/lazylms/pkg/tui/styles"
+)
+
+// renderModelDetailsView renders the details of the highlighted downloaded
+// model in place of the logs, with what would have to be unloaded for it
+func (m Model) renderModelDetailsView(mainHeight, rightColumnWidth int) string {
+	width := rightColumnWidth - 6
+	label := lipgloss.NewStyle().Foreground(styles.ColorGray)
+
+	var lines []string
+	item, ok := m.downloadedList.SelectedItem().(downloadedModelItem)
+	if !ok {
+		lines = append(lines, label.Render("No model highlighted"))
+	} else {
+		model := item.model
+		lines = append(lines,
+			lipgloss.NewStyle().Bold(true).Render(model.DisplayName)+label.Render("  "+model.ModelKey),
+			label.Render("Format ")+detailsOrDash(model.Format)+
+				label.Render("  Quantization ")+detailsOrDash(model.Quantization.Name)+
+				label.Render("  Architecture ")+detailsOrDash(model.Architecture),
+			label.Render("Size ")+client.FormatBytes(model.SizeBytes)+
+				label.Render("  Max context ")+detailsCount(model.MaxContextLength),
+		)
+		lines = append(lines, m.renderEstimateDetails(model, width)...)
+	}
+
+	embeddedText := map[layout.BorderPosition]string{
+		layout.TopLeftBorder: "ⓘ Model Details",
+	}
+
+	content := lipgloss.NewStyle().Padding(0, 1).Render(strings.Join(lines, "\n"))
+
+	return layout.Borderize(content, false, rightColumnWidth-2, (mainHeight)/4, embeddedText)
+}
+
+// renderEstimateDetails describes the load estimate of a model and, when it
+// would not fit, the loaded models that could make room for it
+func (m Model) renderEstimateDetails(model client.LMSDownloadedListItem, width int) []string {
+	label := lipgloss.NewStyle().Foreground(styles.ColorGray)
+	wrap := lipgloss.NewStyle().Width(width)
+
+	switch {
+	case model.EstimateErr != nil:
+		return []string{wrap.Foreground(styles.ColorGray).Render("? Estimate failed: " + model.EstimateErr.Error())}
+	case model.Estimate == nil:
+		if m.estimating {
+			return []string{label.Render("… Estimating")}
+		}
+		if err := m.client.CanManageModels(); err != nil {
+			return []string{wrap.Foreground(styles.ColorGray).Render("No estimates: " + err.Error())}
+		}
+		return []string{label.Render("No estimate yet")}
+	}
+
+	estimate := model.Estimate
+	verdict := lipgloss.NewStyle().Foreground(styles.ColorGreen).Render("● Fits")
+	if estimate.Status != client.StatusAvailable {
+		verdict = lipgloss.NewStyle().Foreground(styles.ColorYellow).Render("⚠ May not fit")
+	}
+	lines := []string{verdict +
+		label.Render("  GPU memory ") + client.FormatBytes(estimate.GPUMemory) +
+		label.Render("  Total memory ") + client.FormatBytes(estimate.TotalMemory)}
+	if reason := strings.TrimSpace(estimate.Verdict + " " + estimate.Reason); reason != "" {
+		lines = append(lines, wrap.Foreground(styles.ColorGray).Render(reason))
+	}
+
+	if estimate.Status == client.StatusAvailable || len(m.loadedModels) == 0 {
+		return lines
+	}
+
+	// Largest first, since unloading those frees the most memory
+	loaded := slices.Clone(m.loadedModels)
+	slices.SortFunc(loaded, func(a, b client.LMSLoadedListItem) int {
+		return cmp.Compare(b.SizeBytes, a.SizeBytes)
+	})
+	names := make([]string, 0, len(loaded))
+	for _, model := range loaded {
+		names = append(names, fmt.Sprintf("%s (%s)", model.Identifier, client.FormatBytes(model.SizeBytes)))
+	}
+	lines = append(lines, wrap.Render(label.Render("Loaded: ")+strings.Join(names, ", ")+label.Render(" · press u in [2] to unload")))
+	return lines
+}
+
+func detailsOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func detailsCount(value int) string {
+	if value <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d", value)
+}
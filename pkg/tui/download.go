@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+	"github.com/Rugz007/lazylms/pkg/tui/keybindings"
+	"github.com/Rugz007/lazylms/pkg/tui/layout"
+	"github.com/Rugz007/lazylms/pkg/tui/styles"
+)
+
+const (
+	downloadPopupWidth = 60
+	downloadBarWidth   = 10
+)
+
+// openDownloadPopup asks for the model to download, unless one is already
+// downloading
+func (m *Model) openDownloadPopup() tea.Cmd {
+	if m.download != nil {
+		model := m.download.Model
+		return func() tea.Msg {
+			return logMsg(fmt.Sprintf("Already downloading %s, press x to cancel it", model))
+		}
+	}
+	m.showDownloadPopup = true
+	m.downloadInput.Focus()
+	m.chatInput.Blur()
+	return nil
+}
+
+// closeDownloadPopup hides the popup
+func (m *Model) closeDownloadPopup() {
+	m.showDownloadPopup = false
+	m.downloadMatch = ""
+	m.downloadResolving = false
+	m.downloadInput.Blur()
+}
+
+// handleDownloadPopupKeys handles keys while the download popup is open
+func (m Model) handleDownloadPopupKeys(msg tea.KeyMsg, globalKeyMap keybindings.GlobalKeyMap) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case globalKeyMap.Quit.Keys()[0]:
+		if m.cancel != nil {
+			m.cancel()
+		}
+		return m, tea.Quit
+	case "enter":
+		if m.downloadMatch != "" {
+			return m, m.startDownload(m.downloadMatch)
+		}
+		return m, m.resolveDownload()
+	case "esc":
+		if m.downloadMatch != "" {
+			// Back to the search term
+			m.downloadMatch = ""
+			return m, nil
+		}
+		m.closeDownloadPopup()
+		return m, nil
+	default:
+		var cmd tea.Cmd
+		m.downloadInput, cmd = m.downloadInput.Update(msg)
+		m.downloadMatch = ""
+		return m, cmd
+	}
+}
+
+// resolveDownload downloads a model key right away and looks a search term
+// up first, so that its match is confirmed before lms downloads it
+func (m *Model) resolveDownload() tea.Cmd {
+	term := strings.TrimSpace(m.downloadInput.Value())
+	if client.IsDownloadKey(term) {
+		return m.startDownload(term)
+	}
+	if m.downloadResolving {
+		return nil
+	}
+
+	m.downloadResolving = true
+	ctx := m.ctx
+	return func() tea.Msg {
+		model, err := client.ResolveDownload(ctx, term)
+		return downloadResolvedMsg{term: term, model: model, err: err}
+	}
+}
+
+// handleDownloadResolved asks to confirm the match of a search term, unless
+// the popup moved on in the meantime
+func (m *Model) handleDownloadResolved(msg downloadResolvedMsg) tea.Cmd {
+	m.downloadResolving = false
+	if !m.showDownloadPopup || strings.TrimSpace(m.downloadInput.Value()) != msg.term {
+		return nil
+	}
+	if msg.err != nil {
+		return func() tea.Msg { return logMsg(fmt.Sprintf("Cannot download %s: %v", msg.term, msg.err)) }
+	}
+	m.downloadMatch = msg.model
+	return nil
+}
+
+// startDownload downloads a model in the background. The download outlives
+// server switches, it only ends with the TUI.
+func (m *Model) startDownload(model string) tea.Cmd {
+	job, err := m.client.StartDownload(m.ctx, model)
+	if err != nil {
+		return func() tea.Msg { return logMsg(fmt.Sprintf("Cannot download: %v", err)) }
+	}
+
+	m.closeDownloadPopup()
+	m.downloadInput.SetValue("")
+	m.download = job
+	m.downloadProgress = client.DownloadProgress{Percent: -1}
+	return waitForDownload(job)
+}
+
+// cancelDownload stops the running download, if any
+func (m *Model) cancelDownload() {
+	if m.download != nil {
+		m.download.Cancel()
+	}
+}
+
+// waitForDownload reads the next progress update of a download
+func waitForDownload(job *client.DownloadJob) tea.Cmd {
+	return func() tea.Msg {
+		progress, ok := <-job.Progress()
+		if !ok {
+			return downloadDoneMsg{job: job}
+		}
+		return downloadProgressMsg{job: job, progress: progress}
+	}
+}
+
+// renderDownloadProgress renders the running download for the border of the
+// downloaded models panel
+func (m Model) renderDownloadProgress(width int) string {
+	if m.download == nil {
+		return ""
+	}
+	progress := m.downloadProgress
+
+	gauge := lipgloss.NewStyle().Foreground(styles.ColorGray).Render("…")
+	if progress.Percent >= 0 {
+		filled := int(progress.Percent / 100 * downloadBarWidth)
+		bar := lipgloss.NewStyle().Foreground(styles.ColorGreen).Render(strings.Repeat("█", filled)) +
+			lipgloss.NewStyle().Foreground(styles.ColorGray).Render(strings.Repeat("░", downloadBarWidth-filled))
+		gauge = fmt.Sprintf("%s %3.0f%%", bar, progress.Percent)
+	}
+
+	nameWidth := max(width-lipgloss.Width(gauge)-3, 4)
+	return "↓ " + truncate(m.download.Model, nameWidth) + " " + gauge
+}
+
+// renderDownloadPopup renders the popup asking which model to download
+func (m Model) renderDownloadPopup() string {
+	instructions := "Model key or search term, Enter download, Esc cancel"
+	switch {
+	case m.downloadMatch != "":
+		instructions = fmt.Sprintf("Best match: %s\nEnter download, Esc back", m.downloadMatch)
+	case m.downloadResolving:
+		instructions = "Searching Hugging Face…"
+	}
+	instructionsStyle := lipgloss.NewStyle().
+		Foreground(styles.ColorGray).
+		Align(lipgloss.Center).
+		Width(downloadPopupWidth - 8)
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		"",
+		m.downloadInput.View(),
+		"",
+		instructionsStyle.Render(instructions),
+		"",
+	)
+
+	embeddedText := map[layout.BorderPosition]string{
+		layout.TopLeftBorder: "↓ Get Model",
+	}
+
+	popup := layout.Borderize(content, true, downloadPopupWidth, 8, embeddedText)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
+}
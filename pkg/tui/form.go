@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Rugz007/lazylms/pkg/tui/styles"
+)
+
+// formField is a labelled text input inside a popup form
+type formField struct {
+	label string
+	input textinput.Model
+}
+
+// popupForm is a list of labelled inputs navigated with tab and arrows
+type popupForm struct {
+	fields []formField
+	focus  int
+}
+
+func newPopupForm(width int, labels ...string) popupForm {
+	fields := make([]formField, len(labels))
+	for i, label := range labels {
+		input := textinput.New()
+		input.Width = width
+		input.Prompt = ""
+		fields[i] = formField{label: label, input: input}
+	}
+	return popupForm{fields: fields}
+}
+
+// Focus focuses the current field
+func (f *popupForm) Focus() {
+	for i := range f.fields {
+		if i == f.focus {
+			f.fields[i].input.Focus()
+		} else {
+			f.fields[i].input.Blur()
+		}
+	}
+}
+
+// Blur removes focus from every field
+func (f *popupForm) Blur() {
+	for i := range f.fields {
+		f.fields[i].input.Blur()
+	}
+}
+
+// Next moves focus to the next field, wrapping around
+func (f *popupForm) Next() {
+	f.focus = (f.focus + 1) % len(f.fields)
+	f.Focus()
+}
+
+// Prev moves focus to the previous field, wrapping around
+func (f *popupForm) Prev() {
+	f.focus = (f.focus - 1 + len(f.fields)) % len(f.fields)
+	f.Focus()
+}
+
+// Value returns the trimmed value of a field
+func (f popupForm) Value(i int) string {
+	return strings.TrimSpace(f.fields[i].input.Value())
+}
+
+// SetValue replaces the value of a field
+func (f *popupForm) SetValue(i int, value string) {
+	f.fields[i].input.SetValue(value)
+}
+
+// SetPlaceholder replaces the placeholder of a field
+func (f *popupForm) SetPlaceholder(i int, placeholder string) {
+	f.fields[i].input.Placeholder = placeholder
+}
+
+// Update forwards a message to the focused field
+func (f *popupForm) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	f.fields[f.focus].input, cmd = f.fields[f.focus].input.Update(msg)
+	return cmd
+}
+
+// View renders every field on its own line with aligned labels
+func (f popupForm) View() string {
+	labelWidth := 0
+	for _, field := range f.fields {
+		labelWidth = max(labelWidth, lipgloss.Width(field.label))
+	}
+
+	lines := make([]string, len(f.fields))
+	for i, field := range f.fields {
+		color := styles.ColorGray
+		if i == f.focus {
+			color = styles.ColorPurple
+		}
+		label := lipgloss.NewStyle().
+			Foreground(color).
+			Width(labelWidth + 2).
+			Render(field.label)
+		lines[i] = label + field.input.View()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -21,6 +21,26 @@ func (m Model) View() string {
 		return m.renderSystemPopup()
 	}
 
+	// Show sampling parameters popup if requested
+	if m.showSamplingPopup {
+		return m.renderSamplingPopup()
+	}
+
+	// Show load options popup if requested
+	if m.showLoadPopup {
+		return m.renderLoadPopup()
+	}
+
+	// Show download popup if requested
+	if m.showDownloadPopup {
+		return m.renderDownloadPopup()
+	}
+
+	// Show server switcher if requested
+	if m.showServerPopup {
+		return m.renderServerPopup()
+	}
+
 	// Calculate dimensions
 	footerHeight := 1
 	mainHeight := m.height - footerHeight
@@ -31,7 +51,13 @@ func (m Model) View() string {
 	loadedView := m.renderLoadedModelsView(mainHeight, leftColumnWidth)
 	downloadedView := m.renderDownloadedModelsView(mainHeight, leftColumnWidth)
 	chatView := m.renderChatView(mainHeight, rightColumnWidth)
-	logsView := m.renderLogsView(mainHeight, rightColumnWidth)
+	// The downloaded list shows the details of its highlighted model below the chat
+	var logsView string
+	if m.currentView == "downloaded" {
+		logsView = m.renderModelDetailsView(mainHeight, rightColumnWidth)
+	} else {
+		logsView = m.renderLogsView(mainHeight, rightColumnWidth)
+	}
 	footerView := m.renderFooterView()
 
 	// Left column: status (6 lines) + loaded models + downloaded models
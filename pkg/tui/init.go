@@ -12,6 +12,5 @@ func (m Model) Init() tea.Cmd {
 		m.startLogListening(),
 		m.updateStatusCmd(),
 		m.updateModelsCmd(),
-		m.updateEstimatesCmd(),
 	)
 }
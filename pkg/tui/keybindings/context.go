@@ -16,6 +16,7 @@ type ChatKeyMap struct {
 	End         key.Binding
 	ExitChat    key.Binding
 	Cancel      key.Binding
+	Summaries   key.Binding
 }
 
 func DefaultChatKeyMap() ChatKeyMap {
@@ -56,14 +57,22 @@ func DefaultChatKeyMap() ChatKeyMap {
 			key.WithKeys("ctrl+x"),
 			key.WithHelp("ctrl+x", "cancel request"),
 		),
+		Summaries: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "expand summaries"),
+		),
 	}
 }
 
 // ListKeyMap defines key bindings for list views
 type ListKeyMap struct {
-	Select    key.Binding
-	Unload    key.Binding
-	UnloadAll key.Binding
+	Select         key.Binding
+	Unload         key.Binding
+	UnloadAll      key.Binding
+	Download       key.Binding
+	CancelDownload key.Binding
+	Sort           key.Binding
+	Group          key.Binding
 }
 
 func DefaultListKeyMap() ListKeyMap {
@@ -80,6 +89,22 @@ func DefaultListKeyMap() ListKeyMap {
 			key.WithKeys("U"),
 			key.WithHelp("U", "unload all"),
 		),
+		Download: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "get model"),
+		),
+		CancelDownload: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "cancel download"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort"),
+		),
+		Group: key.NewBinding(
+			key.WithKeys("G"),
+			key.WithHelp("G", "group"),
+		),
 	}
 }
 
@@ -115,6 +140,14 @@ func HandleListKey(msg tea.KeyMsg, keyMap ListKeyMap) (tea.Cmd, bool) {
 		return nil, true // Unload model
 	case keyMap.UnloadAll.Keys()[0]:
 		return nil, true // Unload all
+	case keyMap.Download.Keys()[0]:
+		return nil, true // Get model
+	case keyMap.CancelDownload.Keys()[0]:
+		return nil, true // Cancel download
+	case keyMap.Sort.Keys()[0]:
+		return nil, true // Sort
+	case keyMap.Group.Keys()[0]:
+		return nil, true // Group
 	}
 	return nil, false
 }
@@ -13,6 +13,9 @@ type GlobalKeyMap struct {
 	Help         key.Binding
 	SystemPrompt key.Binding
 	ClearChat    key.Binding
+	Reasoning    key.Binding
+	Sampling     key.Binding
+	Servers      key.Binding
 }
 
 func DefaultGlobalKeyMap() GlobalKeyMap {
@@ -41,6 +44,18 @@ func DefaultGlobalKeyMap() GlobalKeyMap {
 			key.WithKeys("ctrl+l"),
 			key.WithHelp("ctrl+l", "clear chat"),
 		),
+		Reasoning: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "reasoning effort"),
+		),
+		Sampling: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "sampling parameters"),
+		),
+		Servers: key.NewBinding(
+			key.WithKeys("ctrl+o"),
+			key.WithHelp("ctrl+o", "servers"),
+		),
 	}
 }
 
@@ -90,6 +105,12 @@ func HandleGlobalKey(msg tea.KeyMsg, keyMap GlobalKeyMap) (tea.Cmd, bool) {
 		return nil, true // Toggle system prompt
 	case keyMap.ClearChat.Keys()[0]:
 		return nil, true
+	case keyMap.Reasoning.Keys()[0]:
+		return nil, true // Cycle reasoning effort
+	case keyMap.Sampling.Keys()[0]:
+		return nil, true // Toggle sampling popup
+	case keyMap.Servers.Keys()[0]:
+		return nil, true // Toggle server switcher
 	}
 	return nil, false
 }
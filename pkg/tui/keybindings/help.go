@@ -12,6 +12,8 @@ func GetGlobalHelpKeys() []key.Binding {
 		keyMap.Help,
 		keyMap.SystemPrompt,
 		keyMap.ClearChat,
+		keyMap.Reasoning,
+		keyMap.Sampling,
 	}
 }
 
@@ -34,6 +36,7 @@ func GetChatHelpKeys() []key.Binding {
 		keyMap.ScrollDown,
 		keyMap.ExitChat,
 		keyMap.Cancel,
+		keyMap.Summaries,
 	}
 }
 
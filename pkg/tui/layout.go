@@ -19,10 +19,19 @@ NAVIGATION:
 
 MODEL MANAGEMENT:
    ↑/↓, j/k     Navigate in lists
-   Enter        Load model (from downloaded list)
+   Enter        Load model with options (from downloaded list)
    Enter        Select model for chat (from loaded list)
    u            Unload single model (from loaded list)
    U            Unload all models (from loaded list)
+   g            Download a model by key or search term (from downloaded list)
+   x            Cancel the running download (from downloaded list)
+   /            Search by name, publisher, architecture, format or quantization
+   Esc          Clear the search
+   s            Sort by server order, name, size, recently used or loadable
+   G            Group by publisher or architecture, Enter folds a group
+   ⚠ / ?        Model may not fit / its load estimate failed
+                The downloaded list shows the highlighted model's
+                details and memory estimate in place of the logs
 
 CHAT:
     4            Enter chat mode (input field becomes active)
@@ -32,6 +41,36 @@ CHAT:
     ↑/↓          Scroll chat history (when in chat mode)
     PgUp/PgDown  Page up/down in chat history
     Home/End     Go to top/bottom of chat history
+    Ctrl+T       Expand or collapse conversation summaries (--compact)
+    /image PATH  Attach an image to the next message (vision models only)
+    /image       Remove pending image attachments
+    /index DIR   Embed the text files of a directory for retrieval
+    /rag on|off  Add relevant indexed chunks to prompts and cite them
+    /schema FILE Ask for JSON following a schema and validate it (off to stop)
+
+REASONING:
+   Ctrl+R       Cycle reasoning effort (off, low, medium, high)
+                The choice is remembered for the selected model
+
+SAMPLING:
+   Ctrl+G       Open sampling parameters popup
+   Tab/↑↓       Move between fields (in popup)
+   Ctrl+P       Load the next saved preset (in popup)
+   Enter        Apply, saving under the preset name if given
+   Esc          Close sampling popup
+
+LOAD OPTIONS:
+   Enter        Open load options of the selected downloaded model
+   Tab/↑↓       Move between fields (in popup)
+   Ctrl+U       Reset every field to the server default (in popup)
+   Enter        Load the model, remembering the options for it
+   Esc          Close load options popup
+
+SERVERS:
+   Ctrl+O       Open the server switcher with each server's reachability
+   Enter        Switch to the highlighted server (in popup)
+   R            Check reachability again (in popup)
+   Esc          Close server switcher
 
 SYSTEM PROMPT:
    Ctrl+S       Open system prompt popup
@@ -113,10 +152,10 @@ func (m Model) renderFooterView() string {
 		if m.streaming {
 			content = "tab: panels | ctrl+x: cancel | ctrl+l: clear chat | ↑↓/pgup/home: nav | esc: exit"
 		} else {
-			content = "tab: panels | enter: send | ctrl+l: clear chat | ↑↓/pgup/home: nav | esc: exit"
+			content = "tab: panels | enter: send | ctrl+r: reasoning | ctrl+g: sampling | ctrl+o: servers | ctrl+l: clear chat | ↑↓/pgup/home: nav | esc: exit"
 		}
 	} else {
-		content = "1-5: panels | ctrl+s: system prompt | ctrl+l: clear chat | enter: select | h: help | ctrl+c: exit | LazyLMS BETA"
+		content = "1-5: panels | ctrl+s: system prompt | ctrl+g: sampling | ctrl+o: servers | ctrl+l: clear chat | enter: select | h: help | ctrl+c: exit | LazyLMS BETA"
 	}
 	return style.Render(content)
 }
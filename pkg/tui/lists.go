@@ -0,0 +1,411 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+	"github.com/Rugz007/lazylms/pkg/tui/styles"
+)
+
+// modelSort orders the model lists
+type modelSort int
+
+const (
+	sortServer   modelSort = iota // Order the server lists the models in
+	sortName                      // Display name, A to Z
+	sortSize                      // Largest first
+	sortRecent                    // Most recently used first
+	sortLoadable                  // Models that fit first, downloaded list only
+)
+
+func (s modelSort) String() string {
+	return [...]string{"server order", "name", "size", "recent", "loadable"}[s]
+}
+
+// modelGrouping groups the model lists under collapsible headers
+type modelGrouping int
+
+const (
+	groupNone modelGrouping = iota
+	groupPublisher
+	groupArchitecture
+)
+
+func (g modelGrouping) String() string {
+	return [...]string{"none", "publisher", "architecture"}[g]
+}
+
+// listOptions is how a model list is sorted and grouped
+type listOptions struct {
+	sort      modelSort
+	grouping  modelGrouping
+	collapsed map[string]bool // Collapsed group names
+}
+
+// nextSort cycles to the next sort mode, skipping size when no model has
+// a size and loadable when the list has no estimates
+func (o *listOptions) nextSort(withSize, withLoadable bool) {
+	o.sort++
+	if o.sort == sortSize && !withSize {
+		o.sort++
+	}
+	if o.sort > sortLoadable || (o.sort == sortLoadable && !withLoadable) {
+		o.sort = sortServer
+	}
+}
+
+// nextGrouping cycles to the next grouping, expanding every group
+func (o *listOptions) nextGrouping() {
+	o.grouping = (o.grouping + 1) % (groupArchitecture + 1)
+	o.collapsed = nil
+}
+
+// toggleGroup collapses or expands a group
+func (o *listOptions) toggleGroup(name string) {
+	if o.collapsed == nil {
+		o.collapsed = make(map[string]bool)
+	}
+	o.collapsed[name] = !o.collapsed[name]
+}
+
+// modelFields are what the lists sort and group models by
+type modelFields struct {
+	name         string
+	publisher    string
+	architecture string
+	size         int64
+	lastUsed     int64 // Unix milliseconds, 0 when unknown
+	loadable     bool
+}
+
+// groupHeaderItem heads a group of models in a grouped list
+type groupHeaderItem struct {
+	name      string
+	count     int
+	collapsed bool
+}
+
+func (h groupHeaderItem) Title() string {
+	arrow := "▾"
+	if h.collapsed {
+		arrow = "▸"
+	}
+	return fmt.Sprintf("%s %s (%d)", arrow, h.name, h.count)
+}
+func (h groupHeaderItem) Description() string { return "" }
+
+// FilterValue is empty so headers never match a search
+func (h groupHeaderItem) FilterValue() string { return "" }
+
+// renderGroupHeader renders a group header for the list delegates
+func renderGroupHeader(header groupHeaderItem, highlighted bool) string {
+	style := lipgloss.NewStyle().Foreground(styles.ColorPurple).Bold(true).PaddingLeft(2)
+	if highlighted {
+		style = style.Foreground(styles.ColorWhite).
+			PaddingLeft(1).
+			BorderLeft(true).
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderForeground(styles.ColorGray)
+	}
+	return style.Render(header.Title() + "\n")
+}
+
+// arrangeModels sorts models and, when grouping, puts them under headers,
+// leaving out the models of collapsed groups unless the list is searched
+func arrangeModels[T any](models []T, fields func(T) modelFields, options listOptions, searching bool, item func(T) list.Item) []list.Item {
+	sorted := slices.Clone(models)
+	byName := func(a, b T) int {
+		return cmp.Compare(strings.ToLower(fields(a).name), strings.ToLower(fields(b).name))
+	}
+	switch options.sort {
+	case sortName:
+		slices.SortStableFunc(sorted, byName)
+	case sortSize:
+		slices.SortStableFunc(sorted, func(a, b T) int {
+			return cmp.Or(cmp.Compare(fields(b).size, fields(a).size), byName(a, b))
+		})
+	case sortRecent:
+		slices.SortStableFunc(sorted, func(a, b T) int {
+			return cmp.Or(cmp.Compare(fields(b).lastUsed, fields(a).lastUsed), byName(a, b))
+		})
+	case sortLoadable:
+		slices.SortStableFunc(sorted, func(a, b T) int {
+			if fields(a).loadable != fields(b).loadable {
+				if fields(a).loadable {
+					return -1
+				}
+				return 1
+			}
+			return byName(a, b)
+		})
+	}
+
+	if options.grouping == groupNone {
+		items := make([]list.Item, len(sorted))
+		for i, model := range sorted {
+			items[i] = item(model)
+		}
+		return items
+	}
+
+	groups := make(map[string][]T)
+	for _, model := range sorted {
+		name := fields(model).publisher
+		if options.grouping == groupArchitecture {
+			name = fields(model).architecture
+		}
+		name = cmp.Or(name, "unknown")
+		groups[name] = append(groups[name], model)
+	}
+
+	items := make([]list.Item, 0, len(sorted)+len(groups))
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		// The search only sees the items of the list
+		collapsed := options.collapsed[name] && !searching
+		items = append(items, groupHeaderItem{name: name, count: len(groups[name]), collapsed: collapsed})
+		if collapsed {
+			continue
+		}
+		for _, model := range groups[name] {
+			items = append(items, item(model))
+		}
+	}
+	return items
+}
+
+// lastUsed returns when a model was last used, from the server or else
+// from the preferences
+func (m Model) lastUsed(modelKey string, serverLastUsed int64) int64 {
+	if m.preferences == nil {
+		return serverLastUsed
+	}
+	return max(serverLastUsed, m.preferences.LastUsedAt(modelKey))
+}
+
+func (m Model) loadedFields(model client.LMSLoadedListItem) modelFields {
+	return modelFields{
+		name:         model.Identifier,
+		publisher:    model.Publisher,
+		architecture: model.Architecture,
+		size:         model.SizeBytes,
+		lastUsed:     m.lastUsed(model.ModelKey, model.LastUsedTime),
+		loadable:     true,
+	}
+}
+
+func (m Model) downloadedFields(model client.LMSDownloadedListItem) modelFields {
+	return modelFields{
+		name:         model.DisplayName,
+		publisher:    model.Publisher,
+		architecture: model.Architecture,
+		size:         model.SizeBytes,
+		lastUsed:     m.lastUsed(model.ModelKey, 0),
+		loadable:     model.CanLoad && model.EstimateErr == nil,
+	}
+}
+
+// refreshLoadedList rebuilds the loaded list from the loaded models. The
+// command refilters the list when a search is applied.
+func (m *Model) refreshLoadedList() tea.Cmd {
+	searching := m.loadedList.FilterState() != list.Unfiltered
+	items := arrangeModels(m.loadedModels, m.loadedFields, m.loadedListOptions, searching, func(model client.LMSLoadedListItem) list.Item {
+		return loadedModelItem{model: model}
+	})
+	return forList("loaded", m.loadedList.SetItems(items))
+}
+
+// refreshDownloadedList rebuilds the downloaded list from the downloaded
+// models. The command refilters the list when a search is applied.
+func (m *Model) refreshDownloadedList() tea.Cmd {
+	searching := m.downloadedList.FilterState() != list.Unfiltered
+	items := arrangeModels(m.downloadedModels, m.downloadedFields, m.downloadedListOptions, searching, func(model client.LMSDownloadedListItem) list.Item {
+		return downloadedModelItem{model: model}
+	})
+	return forList("downloaded", m.downloadedList.SetItems(items))
+}
+
+// refreshList rebuilds the model list shown by a view
+func (m *Model) refreshList(view string) tea.Cmd {
+	switch view {
+	case "loaded":
+		return m.refreshLoadedList()
+	case "downloaded":
+		return m.refreshDownloadedList()
+	}
+	return nil
+}
+
+// listFor returns the model list shown by a view, or nil
+func (m *Model) listFor(view string) *list.Model {
+	switch view {
+	case "loaded":
+		return &m.loadedList
+	case "downloaded":
+		return &m.downloadedList
+	}
+	return nil
+}
+
+// forList tags the messages of a list command with the view of the list,
+// so that filter results find their way back to it
+func forList(view string, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return listMsg{view: view, msg: cmd()}
+	}
+}
+
+// updateList passes a message to the list of a view
+func (m *Model) updateList(view string, msg tea.Msg) tea.Cmd {
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		cmds := make([]tea.Cmd, len(batch))
+		for i, cmd := range batch {
+			cmds[i] = forList(view, cmd)
+		}
+		return tea.Batch(cmds...)
+	}
+
+	target := m.listFor(view)
+	if target == nil {
+		return nil
+	}
+	wasSearching := target.FilterState() != list.Unfiltered
+	var cmd tea.Cmd
+	*target, cmd = target.Update(msg)
+	if searching := target.FilterState() != list.Unfiltered; searching != wasSearching {
+		// Collapsed groups open up for the search and fold again after it
+		return tea.Batch(forList(view, cmd), m.refreshList(view))
+	}
+	return forList(view, cmd)
+}
+
+// searchingList reports whether a key belongs to the search of the focused
+// list: every key while the search is typed, and esc to clear it
+func (m *Model) searchingList(msg tea.KeyMsg) bool {
+	focused := m.listFor(m.currentView)
+	if focused == nil {
+		return false
+	}
+	return focused.SettingFilter() || (msg.String() == "esc" && focused.IsFiltered())
+}
+
+// cycleListSort changes the sort mode of the focused list
+func (m *Model) cycleListSort() tea.Cmd {
+	switch m.currentView {
+	case "loaded":
+		m.loadedListOptions.nextSort(slices.ContainsFunc(m.loadedModels, func(model client.LMSLoadedListItem) bool {
+			return model.SizeBytes > 0
+		}), false)
+		return tea.Batch(m.refreshLoadedList(), listOptionsLog("Loaded", m.loadedListOptions))
+	case "downloaded":
+		m.downloadedListOptions.nextSort(slices.ContainsFunc(m.downloadedModels, func(model client.LMSDownloadedListItem) bool {
+			return model.SizeBytes > 0
+		}), true)
+		return tea.Batch(m.refreshDownloadedList(), listOptionsLog("Downloaded", m.downloadedListOptions))
+	}
+	return nil
+}
+
+// cycleListGrouping changes the grouping of the focused list
+func (m *Model) cycleListGrouping() tea.Cmd {
+	switch m.currentView {
+	case "loaded":
+		m.loadedListOptions.nextGrouping()
+		m.loadedList.Select(0)
+		return tea.Batch(m.refreshLoadedList(), listOptionsLog("Loaded", m.loadedListOptions))
+	case "downloaded":
+		m.downloadedListOptions.nextGrouping()
+		m.downloadedList.Select(0)
+		return tea.Batch(m.refreshDownloadedList(), listOptionsLog("Downloaded", m.downloadedListOptions))
+	}
+	return nil
+}
+
+// toggleHighlightedGroup collapses or expands the group whose header is
+// highlighted, reporting false when no header is highlighted
+func (m *Model) toggleHighlightedGroup() (tea.Cmd, bool) {
+	focused := m.listFor(m.currentView)
+	if focused == nil {
+		return nil, false
+	}
+	header, ok := focused.SelectedItem().(groupHeaderItem)
+	if !ok {
+		return nil, false
+	}
+
+	var cmd tea.Cmd
+	if m.currentView == "loaded" {
+		m.loadedListOptions.toggleGroup(header.name)
+		cmd = m.refreshLoadedList()
+	} else {
+		m.downloadedListOptions.toggleGroup(header.name)
+		cmd = m.refreshDownloadedList()
+	}
+
+	// Keep the cursor on the header
+	for i, item := range focused.Items() {
+		if other, ok := item.(groupHeaderItem); ok && other.name == header.name {
+			focused.Select(i)
+			break
+		}
+	}
+	return cmd, true
+}
+
+func listOptionsLog(name string, options listOptions) tea.Cmd {
+	return func() tea.Msg {
+		return logMsg(fmt.Sprintf("%s models sorted by %s, grouped by %s", name, options.sort, options.grouping))
+	}
+}
+
+// renderListSummary describes the sort, grouping and search of a list in
+// a single line, empty when the list is shown as the server sent it
+func renderListSummary(options listOptions, search string, width int) string {
+	var parts []string
+	if options.sort != sortServer {
+		parts = append(parts, "↕ "+options.sort.String())
+	}
+	if options.grouping != groupNone {
+		parts = append(parts, "⊞ "+options.grouping.String())
+	}
+	if search != "" {
+		parts = append(parts, "/"+search)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(styles.ColorGray).Render(truncate(strings.Join(parts, " · "), width))
+}
+
+// touchModel remembers that a model was used now, for the recent sort
+func (m *Model) touchModel(modelKey string) tea.Cmd {
+	if m.preferences == nil || modelKey == "" {
+		return nil
+	}
+	if !m.preferences.SetLastUsed(modelKey, time.Now().UnixMilli()) {
+		return nil
+	}
+	return m.savePreferencesCmd()
+}
+
+// savePreferencesCmd writes the preferences in the background
+func (m *Model) savePreferencesCmd() tea.Cmd {
+	preferences := m.preferences
+	return func() tea.Msg {
+		if err := preferences.Save(); err != nil {
+			return logMsg(fmt.Sprintf("Failed to save preferences: %v", err))
+		}
+		return nil
+	}
+}
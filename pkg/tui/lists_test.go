@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// testModelItem is a list item naming a model
+type testModelItem string
+
+func (i testModelItem) FilterValue() string { return string(i) }
+
+func TestArrangeModels(t *testing.T) {
+	models := []modelFields{
+		{name: "qwen3-8b", publisher: "qwen", size: 5, lastUsed: 1, loadable: true},
+		{name: "Gemma-3", publisher: "google", size: 9, lastUsed: 3},
+		{name: "qwen3-32b", publisher: "qwen", size: 20, lastUsed: 2},
+		{name: "mystery", size: 5, loadable: true},
+	}
+
+	tests := []struct {
+		name      string
+		options   listOptions
+		searching bool
+		want      string
+	}{
+		{name: "server order", options: listOptions{}, want: "qwen3-8b Gemma-3 qwen3-32b mystery"},
+		{name: "name", options: listOptions{sort: sortName}, want: "Gemma-3 mystery qwen3-32b qwen3-8b"},
+		{name: "size ties by name", options: listOptions{sort: sortSize}, want: "qwen3-32b Gemma-3 mystery qwen3-8b"},
+		{name: "recent", options: listOptions{sort: sortRecent}, want: "Gemma-3 qwen3-32b qwen3-8b mystery"},
+		{name: "loadable", options: listOptions{sort: sortLoadable}, want: "mystery qwen3-8b Gemma-3 qwen3-32b"},
+		{
+			name:    "grouped by publisher",
+			options: listOptions{sort: sortName, grouping: groupPublisher},
+			want:    "[google 1] Gemma-3 [qwen 2] qwen3-32b qwen3-8b [unknown 1] mystery",
+		},
+		{
+			name:    "collapsed group",
+			options: listOptions{grouping: groupPublisher, collapsed: map[string]bool{"qwen": true}},
+			want:    "[google 1] Gemma-3 [qwen 2 collapsed] [unknown 1] mystery",
+		},
+		{
+			name:      "collapsed group while searching",
+			options:   listOptions{grouping: groupPublisher, collapsed: map[string]bool{"qwen": true}},
+			searching: true,
+			want:      "[google 1] Gemma-3 [qwen 2] qwen3-8b qwen3-32b [unknown 1] mystery",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := arrangeModels(models, func(f modelFields) modelFields { return f }, tt.options, tt.searching, func(f modelFields) list.Item {
+				return testModelItem(f.name)
+			})
+
+			got := make([]string, len(items))
+			for i, item := range items {
+				switch item := item.(type) {
+				case groupHeaderItem:
+					got[i] = "[" + item.name + " " + strconv.Itoa(item.count)
+					if item.collapsed {
+						got[i] += " collapsed"
+					}
+					got[i] += "]"
+				default:
+					got[i] = item.FilterValue()
+				}
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("arrangeModels() = %s, want %s", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
+
+func TestNextSort(t *testing.T) {
+	tests := []struct {
+		name         string
+		withSize     bool
+		withLoadable bool
+		want         []modelSort
+	}{
+		{name: "every mode", withSize: true, withLoadable: true, want: []modelSort{sortName, sortSize, sortRecent, sortLoadable, sortServer}},
+		{name: "without estimates", withSize: true, want: []modelSort{sortName, sortSize, sortRecent, sortServer}},
+		{name: "without sizes", withLoadable: true, want: []modelSort{sortName, sortRecent, sortLoadable, sortServer}},
+		{name: "without either", want: []modelSort{sortName, sortRecent, sortServer}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options listOptions
+			for _, want := range tt.want {
+				options.nextSort(tt.withSize, tt.withLoadable)
+				if options.sort != want {
+					t.Fatalf("nextSort() = %s, want %s", options.sort, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+	"github.com/Rugz007/lazylms/pkg/tui/keybindings"
+	"github.com/Rugz007/lazylms/pkg/tui/layout"
+	"github.com/Rugz007/lazylms/pkg/tui/styles"
+)
+
+// Fields of the load options popup form
+const (
+	loadFieldContextLength = iota
+	loadFieldGPU
+	loadFieldTTL
+	loadFieldIdentifier
+	loadFieldParallel
+)
+
+const loadPopupWidth = 64
+
+func newLoadForm(model client.LMSDownloadedListItem) popupForm {
+	form := newPopupForm(loadPopupWidth-24, "Context length", "GPU offload", "TTL (seconds)", "Identifier", "Parallel")
+	if model.MaxContextLength > 0 {
+		form.SetPlaceholder(loadFieldContextLength, fmt.Sprintf("default, up to %d", model.MaxContextLength))
+	} else {
+		form.SetPlaceholder(loadFieldContextLength, "default")
+	}
+	form.SetPlaceholder(loadFieldGPU, "max, off or 0-1")
+	form.SetPlaceholder(loadFieldTTL, "never unload")
+	form.SetPlaceholder(loadFieldIdentifier, model.ModelKey)
+	form.SetPlaceholder(loadFieldParallel, "default")
+	return form
+}
+
+// openLoadPopup shows the load options of a downloaded model, filled with
+// the options it was last loaded with
+func (m *Model) openLoadPopup(model client.LMSDownloadedListItem) {
+	m.loadTarget = model
+	m.loadForm = newLoadForm(model)
+	if m.preferences != nil {
+		if options, ok := m.preferences.LoadOptionsFor(model.ModelKey); ok {
+			m.setLoadFormValues(options)
+		}
+	}
+	m.loadForm.Focus()
+	m.showLoadPopup = true
+	m.chatInput.Blur()
+}
+
+// closeLoadPopup hides the popup and gives focus back to the chat input
+func (m *Model) closeLoadPopup() {
+	m.showLoadPopup = false
+	m.loadForm.Blur()
+	if m.currentView == "chat" {
+		m.chatInput.Focus()
+	}
+}
+
+func (m *Model) setLoadFormValues(options client.LoadOptions) {
+	m.loadForm.SetValue(loadFieldContextLength, formatOptionalCount(options.ContextLength))
+	m.loadForm.SetValue(loadFieldGPU, options.GPU)
+	m.loadForm.SetValue(loadFieldTTL, formatOptionalCount(options.TTL))
+	m.loadForm.SetValue(loadFieldIdentifier, options.Identifier)
+	m.loadForm.SetValue(loadFieldParallel, formatOptionalCount(options.Parallel))
+}
+
+// handleLoadPopupKeys handles keys while the load options popup is open
+func (m Model) handleLoadPopupKeys(msg tea.KeyMsg, globalKeyMap keybindings.GlobalKeyMap) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case globalKeyMap.Quit.Keys()[0]:
+		if m.cancel != nil {
+			m.cancel()
+		}
+		return m, tea.Quit
+	case "tab", "down":
+		m.loadForm.Next()
+		return m, nil
+	case "shift+tab", "up":
+		m.loadForm.Prev()
+		return m, nil
+	case "ctrl+u":
+		// Back to the server defaults
+		m.setLoadFormValues(client.LoadOptions{})
+		return m, nil
+	case "enter":
+		return m, m.applyLoadForm()
+	case "esc":
+		m.closeLoadPopup()
+		return m, nil
+	default:
+		return m, m.loadForm.Update(msg)
+	}
+}
+
+// applyLoadForm loads the model with the form options and remembers them
+func (m *Model) applyLoadForm() tea.Cmd {
+	options, err := parseLoadForm(m.loadForm)
+	if err == nil {
+		err = client.ValidateLoadOptions(options, m.loadTarget.MaxContextLength)
+	}
+	if err != nil {
+		return func() tea.Msg { return logMsg(fmt.Sprintf("Invalid load options: %v", err)) }
+	}
+
+	modelKey := m.loadTarget.ModelKey
+	m.closeLoadPopup()
+	m.client.GetLogger().Info("Loading model: %s", modelKey)
+	cmds := []tea.Cmd{m.loadModelCmd(modelKey, options)}
+
+	if m.preferences != nil {
+		m.preferences.SetLoadOptions(modelKey, options)
+		m.preferences.SetLastUsed(modelKey, time.Now().UnixMilli())
+		cmds = append(cmds, m.savePreferencesCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+// parseLoadForm converts the form fields into load options, leaving empty
+// fields at the server defaults
+func parseLoadForm(form popupForm) (client.LoadOptions, error) {
+	options := client.LoadOptions{
+		GPU:        form.Value(loadFieldGPU),
+		Identifier: form.Value(loadFieldIdentifier),
+	}
+	var err error
+
+	if options.ContextLength, err = parseOptionalCount(form.Value(loadFieldContextLength)); err != nil {
+		return options, fmt.Errorf("context length: %w", err)
+	}
+	if options.TTL, err = parseOptionalCount(form.Value(loadFieldTTL)); err != nil {
+		return options, fmt.Errorf("TTL: %w", err)
+	}
+	if options.Parallel, err = parseOptionalCount(form.Value(loadFieldParallel)); err != nil {
+		return options, fmt.Errorf("parallel: %w", err)
+	}
+	return options, nil
+}
+
+// parseOptionalCount parses a positive whole number, zero when empty
+func parseOptionalCount(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("%q is not a positive whole number", value)
+	}
+	return parsed, nil
+}
+
+func formatOptionalCount(value int) string {
+	if value <= 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+// renderLoadPopup renders the load options popup
+func (m Model) renderLoadPopup() string {
+	instructions := "Tab/↑↓ move, Ctrl+U defaults, Enter load, Esc cancel"
+	instructionsStyle := lipgloss.NewStyle().
+		Foreground(styles.ColorGray).
+		Align(lipgloss.Center).
+		Width(loadPopupWidth - 8)
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		"",
+		m.loadForm.View(),
+		"",
+		instructionsStyle.Render(instructions),
+		"",
+	)
+
+	embeddedText := map[layout.BorderPosition]string{
+		layout.TopLeftBorder: "⇪ Load " + truncate(m.loadTarget.ModelKey, loadPopupWidth-16),
+	}
+
+	popup := layout.Borderize(content, true, loadPopupWidth, 11, embeddedText)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
+}
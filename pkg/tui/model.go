@@ -19,8 +19,11 @@ type ResponseBuffer struct {
 
 func (rb *ResponseBuffer) AddSegment(text string, contentType string) {
 	segType := rendering.ContentTypeOutput
-	if contentType == "reasoning" {
+	switch contentType {
+	case client.ContentTypeReasoning:
 		segType = rendering.ContentTypeReasoning
+	case client.ContentTypeTool:
+		segType = rendering.ContentTypeTool
 	}
 
 	// Merge with last segment if same type
@@ -48,24 +51,45 @@ type Model struct {
 	chatMessages            []rendering.ChatMessage
 	downloadedModels        []client.LMSDownloadedListItem
 	loadedModels            []client.LMSLoadedListItem
-	selectedModel           string // Currently selected model for chat
-	explicitlySelectedModel string // Explicitly selected model via Enter key
-	systemPrompt            string // System prompt for chat
+	selectedModel           string                   // Currently selected model for chat
+	explicitlySelectedModel string                   // Explicitly selected model via Enter key
+	systemPrompt            string                   // System prompt for chat
+	preferences             *client.Preferences      // Choices remembered across sessions
+	activePreset            string                   // Name of the sampling preset in use, if any
+	contextTokens           int                      // Estimated tokens of the conversation history
+	pendingImages           []client.ImageAttachment // Images attached to the next message
+	responseSources         []string                 // Citations retrieved for the current response
 	ctx                     context.Context
 	cancel                  context.CancelFunc
 	logChan                 chan string // Channel for receiving log messages
 	loadedList              list.Model
 	downloadedList          list.Model
+	loadedListOptions       listOptions // Sort and grouping of the loaded list
+	downloadedListOptions   listOptions // Sort and grouping of the downloaded list
 	logsViewport            viewport.Model
 	chatViewport            viewport.Model
 	chatInput               textinput.Model
 	systemInput             textinput.Model // Input for system prompt
 	showHelp                bool
-	showSystemPopup         bool            // Whether to show system prompt popup
-	hasWelcomeMessage       bool            // Whether the welcome message is still displayed
-	animationTime           time.Time       // Current time for animations
-	streaming               bool            // Whether we're currently streaming a response
-	currentResponse         *ResponseBuffer // Buffer for current streaming response with segments
+	showSystemPopup         bool                         // Whether to show system prompt popup
+	showSamplingPopup       bool                         // Whether to show sampling parameters popup
+	samplingForm            popupForm                    // Form for sampling parameters
+	showLoadPopup           bool                         // Whether to show the load options popup
+	loadForm                popupForm                    // Form for the load options
+	loadTarget              client.LMSDownloadedListItem // Model the load options popup loads
+	showServerPopup         bool                         // Whether to show the server switcher
+	showDownloadPopup       bool                         // Whether to show the download popup
+	downloadInput           textinput.Model              // Input for the model to download
+	downloadMatch           string                       // Model a search term resolved to, awaiting confirmation
+	downloadResolving       bool                         // Whether the search term of the popup is being resolved
+	download                *client.DownloadJob          // Running download, nil when idle
+	downloadProgress        client.DownloadProgress      // Latest progress of the running download
+	servers                 []serverEntry                // Servers offered by the server switcher
+	serverCursor            int                          // Server highlighted in the server switcher
+	hasWelcomeMessage       bool                         // Whether the welcome message is still displayed
+	animationTime           time.Time                    // Current time for animations
+	streaming               bool                         // Whether we're currently streaming a response
+	currentResponse         *ResponseBuffer              // Buffer for current streaming response with segments
 	// streamChan is used for streaming response chunks from the API to the UI.
 	//
 	// Synchronization guarantees:
@@ -85,9 +109,13 @@ type Model struct {
 	lastEstimateTime        time.Time // Last time estimates were run
 	lastLoadedModelIDs      []string  // IDs of loaded models from last estimate run
 	firstLoad               bool      // Whether this is the first load
+	estimating              bool      // Whether load estimates are running
+	estimatesQueued         bool      // Whether another estimate run follows the current one
+	estimateFailures        int       // Estimates of the current run that failed
+	estimateErr             error     // First failure of the current run
 }
 
-func NewModel(lmsClient *client.Client, logChannel chan string) Model {
+func NewModel(lmsClient *client.Client, logChannel chan string, servers []client.ServerProfile) Model {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	streamChan := make(chan interface{}, StreamChannelBufferSize)
@@ -126,6 +154,12 @@ func NewModel(lmsClient *client.Client, logChannel chan string) Model {
 	systemInput.CharLimit = SystemInputCharLimit
 	systemInput.Width = 50
 
+	// Initialize download input
+	downloadInput := textinput.New()
+	downloadInput.Placeholder = DownloadInputPlaceholder
+	downloadInput.CharLimit = DownloadInputLimit
+	downloadInput.Width = 44
+
 	// Check if any models are loaded and update placeholder accordingly
 	if loadedModels, err := lmsClient.GetLoadedModels(); err == nil && len(loadedModels) == 0 {
 		chatInput.Placeholder = NoModelsPlaceholder
@@ -134,6 +168,21 @@ func NewModel(lmsClient *client.Client, logChannel chan string) Model {
 	// Create welcoming message
 	chatViewport.SetContent(WelcomeMessage)
 
+	preferences, err := client.LoadPreferences()
+	if err != nil {
+		lmsClient.GetLogger().Warn("Failed to load preferences: %v", err)
+	}
+
+	// Start with the system prompt of the config file
+	systemPrompt := ""
+	if configured := lmsClient.GetConfig().SystemPrompt; configured != "" {
+		if err := lmsClient.SetSystemMessage(configured); err != nil {
+			lmsClient.GetLogger().Warn("Ignoring configured system prompt: %v", err)
+		} else {
+			systemPrompt = configured
+		}
+	}
+
 	return Model{
 		client:             lmsClient,
 		currentView:        "status",
@@ -141,12 +190,16 @@ func NewModel(lmsClient *client.Client, logChannel chan string) Model {
 		cancel:             cancel,
 		logChan:            logChannel,
 		chatMessages:       []rendering.ChatMessage{},
+		preferences:        preferences,
+		systemPrompt:       systemPrompt,
+		servers:            newServerEntries(lmsClient.GetConfig(), servers),
 		loadedList:         loadedList,
 		downloadedList:     downloadedList,
 		logsViewport:       logsViewport,
 		chatViewport:       chatViewport,
 		chatInput:          chatInput,
 		systemInput:        systemInput,
+		downloadInput:      downloadInput,
 		showHelp:           false,
 		showSystemPopup:    false,
 		hasWelcomeMessage:  true,
@@ -3,6 +3,7 @@ package tui
 import (
 	"io"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/Rugz007/lazylms/pkg/client"
 	"github.com/Rugz007/lazylms/pkg/tui/animation"
+	"github.com/Rugz007/lazylms/pkg/tui/keybindings"
 	"github.com/Rugz007/lazylms/pkg/tui/layout"
 	"github.com/Rugz007/lazylms/pkg/tui/styles"
 )
@@ -81,7 +83,11 @@ func (d downloadedModelItem) Description() string {
 	return format + " - " + d.model.Quantization.Name
 }
 
-func (d downloadedModelItem) FilterValue() string { return d.model.DisplayName }
+// FilterValue is what the search matches, so models can be found by
+// publisher, architecture, format or quantization as well
+func (d downloadedModelItem) FilterValue() string {
+	return strings.Join([]string{d.model.DisplayName, d.model.ModelKey, d.model.Publisher, d.model.Architecture, d.model.Format, d.model.Quantization.Name}, " ")
+}
 
 func (m loadedModelItem) Title() string {
 	return m.model.Identifier
@@ -89,7 +95,9 @@ func (m loadedModelItem) Title() string {
 func (m loadedModelItem) Description() string {
 	return m.model.Status
 }
-func (m loadedModelItem) FilterValue() string { return m.model.Identifier }
+func (m loadedModelItem) FilterValue() string {
+	return strings.Join([]string{m.model.Identifier, m.model.DisplayName, m.model.Publisher, m.model.Architecture, m.model.Format, m.model.Quantization.Name}, " ")
+}
 
 // Custom delegate for downloaded models
 type customDownloadedDelegate struct {
@@ -122,6 +130,10 @@ func (d *customLoadedDelegate) SetAnimationTime(t time.Time)           { d.anima
 func (d *customLoadedDelegate) SetSelectedModel(s string)              { d.selectedModel = s }
 
 func (d customLoadedDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if header, ok := item.(groupHeaderItem); ok {
+		io.WriteString(w, renderGroupHeader(header, index == m.Index()))
+		return
+	}
 	if i, ok := item.(loadedModelItem); ok {
 		highlighted := index == m.Index()
 		explicitlySelected := i.model.Identifier == d.selectedModel
@@ -143,14 +155,20 @@ func (d customLoadedDelegate) Render(w io.Writer, m list.Model, index int, item
 }
 
 func (d customDownloadedDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if header, ok := item.(groupHeaderItem); ok {
+		io.WriteString(w, renderGroupHeader(header, index == m.Index()))
+		return
+	}
 	if i, ok := item.(downloadedModelItem); ok {
 		selected := index == m.Index()
-		var title string
-		if !i.model.CanLoad {
-			title = lipgloss.NewStyle().Foreground(styles.ColorYellow).Render("⚠") + " " + i.model.DisplayName
-		} else {
-			title = i.model.DisplayName
+		// ? marks models whose estimate failed, ⚠ those that would not fit
+		marker := ""
+		if i.model.EstimateErr != nil {
+			marker = lipgloss.NewStyle().Foreground(styles.ColorGray).Render("?") + " "
+		} else if !i.model.CanLoad {
+			marker = lipgloss.NewStyle().Foreground(styles.ColorYellow).Render("⚠") + " "
 		}
+		title := marker + i.model.DisplayName
 
 		desc := i.Description()
 
@@ -165,11 +183,7 @@ func (d customDownloadedDelegate) Render(w io.Writer, m list.Model, index int, i
 				Render(styledTitle + "\n" + styledDesc)
 			io.WriteString(w, styledContent)
 		} else {
-			if !i.model.CanLoad {
-				title = lipgloss.NewStyle().Foreground(styles.ColorYellow).Render("⚠") + " " + lipgloss.NewStyle().Foreground(styles.ColorGray).Render(i.model.DisplayName)
-			} else {
-				title = lipgloss.NewStyle().Foreground(styles.ColorGray).Render(title)
-			}
+			title = marker + lipgloss.NewStyle().Foreground(styles.ColorGray).Render(i.model.DisplayName)
 			desc = lipgloss.NewStyle().Foreground(styles.ColorGray).Render(desc)
 			styledContent := lipgloss.NewStyle().PaddingLeft(2).Render(title + "\n" + desc)
 			io.WriteString(w, styledContent)
@@ -186,7 +200,7 @@ func (m Model) renderLoadedModelsView(mainHeight, leftColumnWidth int) string {
 		content = lipgloss.NewStyle().Padding(1).Render("Server is OFF")
 	} else {
 		m.loadedList.SetShowStatusBar(false)
-		m.loadedList.SetFilteringEnabled(false)
+		m.loadedList.SetShowFilter(m.loadedList.SettingFilter())
 		m.loadedList.SetShowTitle(false)
 		m.loadedList.AdditionalShortHelpKeys = func() []key.Binding {
 			selectBinding := key.NewBinding(
@@ -201,8 +215,9 @@ func (m Model) renderLoadedModelsView(mainHeight, leftColumnWidth int) string {
 				key.WithKeys("U"),
 				key.WithHelp("U", "unload all"),
 			)
+			listKeyMap := keybindings.DefaultListKeyMap()
 			var keyBindings []key.Binding
-			keyBindings = append(keyBindings, selectBinding, unloadBinding, unloadAllBinding)
+			keyBindings = append(keyBindings, selectBinding, unloadBinding, unloadAllBinding, listKeyMap.Sort, listKeyMap.Group)
 			return keyBindings
 		}
 
@@ -224,7 +239,12 @@ func (m Model) renderLoadedModelsView(mainHeight, leftColumnWidth int) string {
 		loadedDelegate.SetSelectedModel(m.explicitlySelectedModel)
 		m.loadedList.SetDelegate(loadedDelegate)
 
-		content = lipgloss.NewStyle().Padding(1).Render(m.loadedList.View())
+		content = m.loadedList.View()
+		if summary := renderListSummary(m.loadedListOptions, m.loadedList.FilterValue(), m.loadedList.Width()); summary != "" {
+			m.loadedList.SetHeight(m.loadedList.Height() - 1)
+			content = lipgloss.JoinVertical(lipgloss.Left, summary, m.loadedList.View())
+		}
+		content = lipgloss.NewStyle().Padding(1).Render(content)
 	}
 
 	embeddedText := map[layout.BorderPosition]string{
@@ -251,20 +271,30 @@ func (m Model) renderDownloadedModelsView(mainHeight, leftColumnWidth int) strin
 				key.WithKeys("enter"),
 				key.WithHelp("enter", "load model"),
 			)
+			listKeyMap := keybindings.DefaultListKeyMap()
 			var keyBindings []key.Binding
-			keyBindings = append(keyBindings, keyBinding)
+			keyBindings = append(keyBindings, keyBinding, listKeyMap.Download, listKeyMap.Sort, listKeyMap.Group)
+			if m.download != nil {
+				keyBindings = append(keyBindings, listKeyMap.CancelDownload)
+			}
 			return keyBindings
 		}
 		m.downloadedList.SetShowTitle(false)
 		m.downloadedList.SetShowStatusBar(false)
-		m.downloadedList.SetFilteringEnabled(false)
+		m.downloadedList.SetShowFilter(m.downloadedList.SettingFilter())
 		m.downloadedList.SetDelegate(downloadedDelegate)
 
-		content = lipgloss.NewStyle().Padding(1).Render(m.downloadedList.View())
+		content = m.downloadedList.View()
+		if summary := renderListSummary(m.downloadedListOptions, m.downloadedList.FilterValue(), m.downloadedList.Width()); summary != "" {
+			m.downloadedList.SetHeight(m.downloadedList.Height() - 1)
+			content = lipgloss.JoinVertical(lipgloss.Left, summary, m.downloadedList.View())
+		}
+		content = lipgloss.NewStyle().Padding(1).Render(content)
 	}
 
 	embeddedText := map[layout.BorderPosition]string{
-		layout.TopLeftBorder: "[3] ▼ Downloaded Models",
+		layout.TopLeftBorder:    "[3] ▼ Downloaded Models",
+		layout.BottomLeftBorder: m.renderDownloadProgress(leftColumnWidth - 6),
 	}
 
 	return layout.Borderize(content, active, leftColumnWidth-2, (mainHeight-6)/2, embeddedText)
@@ -1,11 +1,15 @@
 package rendering
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/Rugz007/lazylms/pkg/client"
 	"github.com/Rugz007/lazylms/pkg/tui/styles"
 )
 
@@ -13,8 +17,9 @@ import (
 type MessageType string
 
 const (
-	MessageTypeUser MessageType = "user"
-	MessageTypeAI   MessageType = "ai"
+	MessageTypeUser    MessageType = "user"
+	MessageTypeAI      MessageType = "ai"
+	MessageTypeSummary MessageType = "summary" // Model-written summary of compacted turns
 )
 
 // ContentType represents the type of content in a segment
@@ -23,6 +28,8 @@ type ContentType string
 const (
 	ContentTypeOutput    ContentType = "output"
 	ContentTypeReasoning ContentType = "reasoning"
+	ContentTypeTool      ContentType = "tool"
+	ContentTypeJSON      ContentType = "json" // Structured output shown without markdown
 )
 
 // ContentSegment represents a segment of content with a specific type
@@ -60,12 +67,45 @@ func WrapText(text string, width int) string {
 	return strings.Join(lines, "\n")
 }
 
+// wrapLines wraps every line of text separately, keeping line breaks
+func wrapLines(text string, width int) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = WrapText(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapIndented wraps every line of text separately, keeping its indentation
+func wrapIndented(text string, width int) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := line[:len(line)-len(trimmed)]
+		wrapped := WrapText(trimmed, width-len(indent))
+		lines[i] = indent + strings.ReplaceAll(wrapped, "\n", "\n"+indent)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // ChatMessage represents a structured chat message
 type ChatMessage struct {
-	Type     MessageType      `json:"type"`     // MessageTypeUser or MessageTypeAI
-	Author   string           `json:"author"`   // "You" or model name
-	Content  string           `json:"content"`  // The actual message content (for simple messages)
-	Segments []ContentSegment `json:"segments"` // Segments with mixed content types
+	Type     MessageType      `json:"type"`               // MessageTypeUser, MessageTypeAI or MessageTypeSummary
+	Author   string           `json:"author"`             // "You" or model name
+	Content  string           `json:"content"`            // The actual message content (for simple messages)
+	Preset   string           `json:"preset,omitempty"`   // Sampling preset that produced an AI message
+	Metrics  *MessageMetrics  `json:"metrics,omitempty"`  // Token usage and timings of an AI message
+	Segments []ContentSegment `json:"segments"`           // Segments with mixed content types
+	Expanded bool             `json:"expanded,omitempty"` // Whether a summary message shows its full text
+	Images   []string         `json:"images,omitempty"`   // Names of the images attached to a user message
+	Sources  []string         `json:"sources,omitempty"`  // Citations of the indexed chunks given to an AI message
+	Schema   *SchemaStatus    `json:"schema,omitempty"`   // Validation of a structured AI message
+}
+
+// SchemaStatus is the result of validating a structured response
+type SchemaStatus struct {
+	Name       string `json:"name"`
+	Violations int    `json:"violations"`
 }
 
 // renderMarkdown renders markdown content with proper styling
@@ -98,20 +138,20 @@ func RenderChatMessage(message ChatMessage, width int, logChan chan string) stri
 			Foreground(styles.ColorGreen).
 			Bold(true).
 			Render(message.Author + ":")
-		return userPrefix + " " + WrapText(message.Content, width-len(message.Author)-2) + "\n"
+		return userPrefix + " " + WrapText(message.Content, width-len(message.Author)-2) + renderImages(message.Images) + "\n"
 	}
 
-	// Style the model name prefix
-	styledPrefix := lipgloss.NewStyle().
-		Foreground(styles.ColorPurple).
-		Bold(true).
-		Render(message.Author + ":")
+	if message.Type == MessageTypeSummary {
+		return renderSummaryMessage(message, width)
+	}
+
+	styledPrefix := RenderAIPrefix(message.Author, message.Preset)
 
 	// AI messages - check if we have segments or simple content
 	if len(message.Segments) > 0 {
 		// Render mixed content with segments
 		rendered := RenderMixedContent(message.Segments, width, logChan)
-		return styledPrefix + rendered + "\n"
+		return styledPrefix + rendered + renderSources(message.Sources) + renderSchemaStatus(message.Schema) + renderMetrics(message.Metrics) + "\n"
 	}
 
 	// Fallback to simple content rendering
@@ -120,7 +160,97 @@ func RenderChatMessage(message ChatMessage, width int, logChan chan string) stri
 		rendered = WrapText(message.Content, width)
 	}
 
-	return styledPrefix + rendered + "\n"
+	return styledPrefix + rendered + renderSources(message.Sources) + renderSchemaStatus(message.Schema) + renderMetrics(message.Metrics) + "\n"
+}
+
+// renderImages lists the images attached to a message
+func renderImages(images []string) string {
+	if len(images) == 0 {
+		return ""
+	}
+	return "\n" + lipgloss.NewStyle().
+		Foreground(styles.ColorGray).
+		Render("📎 "+strings.Join(images, ", "))
+}
+
+// renderSources lists the indexed chunks an answer could cite
+func renderSources(sources []string) string {
+	if len(sources) == 0 {
+		return ""
+	}
+	return "\n" + lipgloss.NewStyle().
+		Foreground(styles.ColorBlue).
+		Render("Sources:\n"+strings.Join(sources, "\n"))
+}
+
+// renderSchemaStatus notes whether a structured response matched its schema
+func renderSchemaStatus(status *SchemaStatus) string {
+	if status == nil {
+		return ""
+	}
+	if status.Violations == 0 {
+		return "\n" + lipgloss.NewStyle().
+			Foreground(styles.ColorGreen).
+			Render("✓ matches schema "+status.Name)
+	}
+	return "\n" + lipgloss.NewStyle().
+		Foreground(styles.ColorOrange).
+		Render(fmt.Sprintf("✗ %d schema violation(s) against %s, see logs", status.Violations, status.Name))
+}
+
+// PrettyJSON indents text when it is a single JSON value, ignoring a
+// surrounding markdown code fence
+func PrettyJSON(text string) (string, bool) {
+	text = client.StripCodeFence(text)
+	if !json.Valid([]byte(text)) {
+		return "", false
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, []byte(text), "", "  "); err != nil {
+		return "", false
+	}
+	return pretty.String(), true
+}
+
+// renderSummaryMessage renders a compaction summary, collapsed to a single
+// line unless expanded
+func renderSummaryMessage(message ChatMessage, width int) string {
+	summaryStyle := lipgloss.NewStyle().
+		Foreground(styles.ColorGray).
+		Italic(true)
+
+	if !message.Expanded {
+		return summaryStyle.Render("▸ Earlier conversation summarized (ctrl+t to expand)") + "\n"
+	}
+
+	header := summaryStyle.Render("▾ Earlier conversation summarized (ctrl+t to collapse)")
+	body := lipgloss.NewStyle().
+		Foreground(styles.ColorGray).
+		BorderLeft(true).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(styles.ColorGray).
+		PaddingLeft(1).
+		Render(wrapLines(message.Content, width-4))
+	return header + "\n" + body + "\n"
+}
+
+// RenderAIPrefix styles the model name prefix, noting the sampling preset when set
+func RenderAIPrefix(modelName, preset string) string {
+	prefix := lipgloss.NewStyle().
+		Foreground(styles.ColorPurple).
+		Bold(true).
+		Render(modelName)
+
+	if preset != "" {
+		prefix += lipgloss.NewStyle().
+			Foreground(styles.ColorGray).
+			Render(" [" + preset + "]")
+	}
+
+	return prefix + lipgloss.NewStyle().
+		Foreground(styles.ColorPurple).
+		Bold(true).
+		Render(":")
 }
 
 // RenderAIMessage renders an AI message with model name styling and markdown
@@ -140,12 +270,12 @@ func RenderAIMessage(modelName, content string, width int, logChan chan string)
 	return styledPrefix + rendered + "\n"
 }
 
-// RenderMixedContent renders content with mixed output and reasoning segments
+// RenderMixedContent renders content with mixed output, reasoning and tool segments
 func RenderMixedContent(segments []ContentSegment, width int, logChan chan string) string {
 	var result strings.Builder
 
-	// Add newline after prefix if first segment is reasoning
-	if len(segments) > 0 && segments[0].Type == ContentTypeReasoning {
+	// Add newline after prefix if first segment is not markdown output
+	if len(segments) > 0 && segments[0].Type != ContentTypeOutput {
 		result.WriteString("\n")
 	}
 
@@ -158,10 +288,28 @@ func RenderMixedContent(segments []ContentSegment, width int, logChan chan strin
 				Foreground(styles.ColorReasoning).
 				Italic(true)
 			result.WriteString(reasoningStyle.Render(wrapped))
-			// Add newline after reasoning if followed by output
-			if i < len(segments)-1 && segments[i+1].Type == ContentTypeOutput {
+			// Add newline after reasoning if followed by another segment
+			if i < len(segments)-1 {
+				result.WriteString("\n")
+			}
+		case ContentTypeTool:
+			// Render tool calls and their results as an indented block
+			wrapped := wrapLines(strings.TrimRight(segment.Text, "\n"), width-4)
+			toolStyle := lipgloss.NewStyle().
+				Foreground(styles.ColorBlue).
+				BorderLeft(true).
+				BorderStyle(lipgloss.NormalBorder()).
+				BorderForeground(styles.ColorBlue).
+				PaddingLeft(1)
+			result.WriteString(toolStyle.Render(wrapped))
+			if i < len(segments)-1 {
 				result.WriteString("\n")
 			}
+		case ContentTypeJSON:
+			// Structured output is already indented, markdown would mangle it
+			result.WriteString(lipgloss.NewStyle().
+				Foreground(styles.ColorWhite).
+				Render(wrapIndented(segment.Text, width)))
 		case ContentTypeOutput:
 			// Render output text with markdown
 			rendered, err := renderMarkdown(segment.Text, width, logChan)
@@ -0,0 +1,28 @@
+package rendering
+
+import "testing"
+
+func TestPrettyJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{name: "object", text: `{"a":1,"b":[true]}`, want: "{\n  \"a\": 1,\n  \"b\": [\n    true\n  ]\n}", wantOK: true},
+		{name: "code fence", text: "```json\n{\"a\":1}\n```", want: "{\n  \"a\": 1\n}", wantOK: true},
+		{name: "scalar", text: `42`, want: "42", wantOK: true},
+		{name: "prose", text: "Here is the answer: {\"a\":1}", wantOK: false},
+		{name: "two values", text: `{"a":1} {"b":2}`, wantOK: false},
+		{name: "empty", text: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PrettyJSON(tt.text)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("PrettyJSON(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
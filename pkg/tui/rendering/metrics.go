@@ -0,0 +1,51 @@
+package rendering
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Rugz007/lazylms/pkg/tui/styles"
+)
+
+// MessageMetrics are the token counts and timings of an AI message
+type MessageMetrics struct {
+	InputTokens      int           `json:"input_tokens"`
+	OutputTokens     int           `json:"output_tokens"`
+	ReasoningTokens  int           `json:"reasoning_tokens,omitempty"`
+	TimeToFirstToken time.Duration `json:"time_to_first_token"`
+	TokensPerSecond  float64       `json:"tokens_per_second"`
+}
+
+// String formats the metrics on a single line, e.g.
+// "120 in · 348 out (96 reasoning) · ttft 0.42s · 41.3 tok/s"
+func (m MessageMetrics) String() string {
+	parts := []string{fmt.Sprintf("%d in", m.InputTokens)}
+
+	out := fmt.Sprintf("%d out", m.OutputTokens)
+	if m.ReasoningTokens > 0 {
+		out += fmt.Sprintf(" (%d reasoning)", m.ReasoningTokens)
+	}
+	parts = append(parts, out)
+
+	if m.TimeToFirstToken > 0 {
+		parts = append(parts, fmt.Sprintf("ttft %.2fs", m.TimeToFirstToken.Seconds()))
+	}
+	if m.TokensPerSecond > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f tok/s", m.TokensPerSecond))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderMetrics renders the metrics line shown under an AI message
+func renderMetrics(metrics *MessageMetrics) string {
+	if metrics == nil {
+		return ""
+	}
+	return "\n" + lipgloss.NewStyle().
+		Foreground(styles.ColorGray).
+		Italic(true).
+		Render(metrics.String())
+}
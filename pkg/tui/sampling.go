@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+	"github.com/Rugz007/lazylms/pkg/tui/keybindings"
+	"github.com/Rugz007/lazylms/pkg/tui/layout"
+	"github.com/Rugz007/lazylms/pkg/tui/styles"
+)
+
+// Fields of the sampling popup form
+const (
+	samplingFieldPreset = iota
+	samplingFieldTemperature
+	samplingFieldTopP
+	samplingFieldMaxTokens
+	samplingFieldStop
+	samplingFieldSeed
+)
+
+const samplingPopupWidth = 60
+
+func newSamplingForm() popupForm {
+	form := newPopupForm(samplingPopupWidth-20, "Preset", "Temperature", "Top P", "Max tokens", "Stop", "Seed")
+	form.SetPlaceholder(samplingFieldPreset, "name to save as (optional)")
+	form.SetPlaceholder(samplingFieldTemperature, "server default")
+	form.SetPlaceholder(samplingFieldTopP, "server default")
+	form.SetPlaceholder(samplingFieldMaxTokens, "server default")
+	form.SetPlaceholder(samplingFieldStop, "comma separated")
+	form.SetPlaceholder(samplingFieldSeed, "random")
+	return form
+}
+
+// openSamplingPopup fills the form with the current settings and shows it
+func (m *Model) openSamplingPopup() {
+	m.samplingForm = newSamplingForm()
+	m.setSamplingFormValues(m.activePreset, m.client.SamplingParams())
+	m.samplingForm.Focus()
+	m.showSamplingPopup = true
+	m.chatInput.Blur()
+}
+
+// closeSamplingPopup hides the popup and gives focus back to the chat input
+func (m *Model) closeSamplingPopup() {
+	m.showSamplingPopup = false
+	m.samplingForm.Blur()
+	if m.currentView == "chat" {
+		m.chatInput.Focus()
+	}
+}
+
+func (m *Model) setSamplingFormValues(preset string, params client.SamplingParams) {
+	m.samplingForm.SetValue(samplingFieldPreset, preset)
+	m.samplingForm.SetValue(samplingFieldTemperature, formatOptionalFloat(params.Temperature))
+	m.samplingForm.SetValue(samplingFieldTopP, formatOptionalFloat(params.TopP))
+	m.samplingForm.SetValue(samplingFieldMaxTokens, formatOptionalInt(params.MaxOutputTokens))
+	m.samplingForm.SetValue(samplingFieldStop, strings.Join(params.Stop, ", "))
+	m.samplingForm.SetValue(samplingFieldSeed, formatOptionalInt(params.Seed))
+}
+
+// handleSamplingPopupKeys handles keys while the sampling popup is open
+func (m Model) handleSamplingPopupKeys(msg tea.KeyMsg, globalKeyMap keybindings.GlobalKeyMap) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case globalKeyMap.Quit.Keys()[0]:
+		if m.cancel != nil {
+			m.cancel()
+		}
+		return m, tea.Quit
+	case "tab", "down":
+		m.samplingForm.Next()
+		return m, nil
+	case "shift+tab", "up":
+		m.samplingForm.Prev()
+		return m, nil
+	case "ctrl+p":
+		m.cyclePreset()
+		return m, nil
+	case "enter":
+		return m, m.applySamplingForm()
+	case "esc", globalKeyMap.Sampling.Keys()[0]:
+		m.closeSamplingPopup()
+		return m, nil
+	default:
+		return m, m.samplingForm.Update(msg)
+	}
+}
+
+// cyclePreset loads the next known preset into the form
+func (m *Model) cyclePreset() {
+	if m.preferences == nil {
+		return
+	}
+	names := m.preferences.PresetNames()
+	if len(names) == 0 {
+		return
+	}
+
+	current := slices.Index(names, m.samplingForm.Value(samplingFieldPreset))
+	name := names[(current+1)%len(names)]
+	params, _ := m.preferences.Preset(name)
+	m.setSamplingFormValues(name, params)
+}
+
+// applySamplingForm applies the form to the client and saves it as a preset
+// when a name is given
+func (m *Model) applySamplingForm() tea.Cmd {
+	params, err := parseSamplingForm(m.samplingForm)
+	if err != nil {
+		return func() tea.Msg { return logMsg(fmt.Sprintf("Invalid sampling parameters: %v", err)) }
+	}
+
+	if err := m.client.SetSamplingParams(params); err != nil {
+		return func() tea.Msg { return logMsg(err.Error()) }
+	}
+
+	name := m.samplingForm.Value(samplingFieldPreset)
+	m.activePreset = name
+	m.closeSamplingPopup()
+
+	if name == "" {
+		return func() tea.Msg { return logMsg("Sampling parameters updated") }
+	}
+
+	if m.preferences == nil {
+		return func() tea.Msg { return logMsg(fmt.Sprintf("Using preset %s", name)) }
+	}
+
+	// Only write presets that are new or changed
+	if existing, ok := m.preferences.Preset(name); ok && reflect.DeepEqual(existing, params) {
+		return func() tea.Msg { return logMsg(fmt.Sprintf("Using preset %s", name)) }
+	}
+
+	if err := m.preferences.SavePreset(name, params); err != nil {
+		return func() tea.Msg { return logMsg(fmt.Sprintf("Failed to save preset: %v", err)) }
+	}
+	preferences := m.preferences
+	return func() tea.Msg {
+		if err := preferences.Save(); err != nil {
+			return logMsg(fmt.Sprintf("Failed to save preferences: %v", err))
+		}
+		return logMsg(fmt.Sprintf("Saved preset %s", name))
+	}
+}
+
+// parseSamplingForm converts the form fields into sampling parameters,
+// leaving empty fields unset
+func parseSamplingForm(form popupForm) (client.SamplingParams, error) {
+	var params client.SamplingParams
+	var err error
+
+	if params.Temperature, err = parseOptionalFloat(form.Value(samplingFieldTemperature)); err != nil {
+		return params, fmt.Errorf("temperature: %w", err)
+	}
+	if params.TopP, err = parseOptionalFloat(form.Value(samplingFieldTopP)); err != nil {
+		return params, fmt.Errorf("top p: %w", err)
+	}
+	if params.MaxOutputTokens, err = parseOptionalInt(form.Value(samplingFieldMaxTokens)); err != nil {
+		return params, fmt.Errorf("max tokens: %w", err)
+	}
+	if params.Seed, err = parseOptionalInt(form.Value(samplingFieldSeed)); err != nil {
+		return params, fmt.Errorf("seed: %w", err)
+	}
+
+	for _, stop := range strings.Split(form.Value(samplingFieldStop), ",") {
+		if stop = strings.TrimSpace(stop); stop != "" {
+			params.Stop = append(params.Stop, stop)
+		}
+	}
+
+	return params, client.ValidateSamplingParams(params)
+}
+
+func parseOptionalFloat(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a number", value)
+	}
+	return &parsed, nil
+}
+
+func parseOptionalInt(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a whole number", value)
+	}
+	return &parsed, nil
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'g', -1, 64)
+}
+
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+// renderSamplingPopup renders the sampling parameters popup
+func (m Model) renderSamplingPopup() string {
+	instructions := "Tab/↑↓ move, Ctrl+P next preset, Enter apply, Esc cancel"
+	instructionsStyle := lipgloss.NewStyle().
+		Foreground(styles.ColorGray).
+		Align(lipgloss.Center).
+		Width(samplingPopupWidth - 8)
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		"",
+		m.samplingForm.View(),
+		"",
+		instructionsStyle.Render(instructions),
+		"",
+	)
+
+	embeddedText := map[layout.BorderPosition]string{
+		layout.TopLeftBorder: "⚙ Sampling Parameters",
+	}
+
+	popup := layout.Borderize(content, true, samplingPopupWidth, 12, embeddedText)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
+}
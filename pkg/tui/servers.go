@@ -0,0 +1,289 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+	"github.com/Rugz007/lazylms/pkg/tui/keybindings"
+	"github.com/Rugz007/lazylms/pkg/tui/layout"
+	"github.com/Rugz007/lazylms/pkg/tui/rendering"
+	"github.com/Rugz007/lazylms/pkg/tui/styles"
+)
+
+const (
+	serverPopupWidth = 70
+	// commandLineServer names the server given on the command line when it
+	// matches no profile
+	commandLineServer = "command line"
+)
+
+// serverEntry is a server offered by the server switcher
+type serverEntry struct {
+	profile client.ServerProfile
+	probe   *client.ServerProbe // nil until the probe answers
+}
+
+// newServerEntries lists the profiles, preceded by the current server when
+// no profile points at it
+func newServerEntries(current client.ClientConfig, profiles []client.ServerProfile) []serverEntry {
+	entries := make([]serverEntry, 0, len(profiles)+1)
+	found := false
+	for _, profile := range profiles {
+		if profile.Err == nil && profile.Config.GetFullURL() == current.GetFullURL() {
+			found = true
+		}
+		entries = append(entries, serverEntry{profile: profile})
+	}
+	if !found {
+		current := serverEntry{profile: client.ServerProfile{Name: commandLineServer, Config: current}}
+		entries = append([]serverEntry{current}, entries...)
+	}
+	return entries
+}
+
+// activeServer returns the index of the entry the client talks to, or -1
+func (m Model) activeServer() int {
+	url := m.client.GetConfig().GetFullURL()
+	for i, entry := range m.servers {
+		if entry.profile.Err == nil && entry.profile.Config.GetFullURL() == url {
+			return i
+		}
+	}
+	return -1
+}
+
+// openServerPopup shows the server switcher and probes every server
+func (m *Model) openServerPopup() tea.Cmd {
+	m.showServerPopup = true
+	m.chatInput.Blur()
+	if active := m.activeServer(); active >= 0 {
+		m.serverCursor = active
+	}
+	return m.probeServersCmd()
+}
+
+// closeServerPopup hides the popup and gives focus back to the chat input
+func (m *Model) closeServerPopup() {
+	m.showServerPopup = false
+	if m.currentView == "chat" {
+		m.chatInput.Focus()
+	}
+}
+
+// probeServersCmd checks the reachability of every server concurrently
+func (m *Model) probeServersCmd() tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range m.servers {
+		m.servers[i].probe = nil
+		if m.servers[i].profile.Err != nil {
+			continue
+		}
+		index, config, ctx := i, m.servers[i].profile.Config, m.ctx
+		cmds = append(cmds, func() tea.Msg {
+			return serverProbeMsg{index: index, probe: client.ProbeServer(ctx, config)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleServerPopupKeys handles keys while the server switcher is open
+func (m Model) handleServerPopupKeys(msg tea.KeyMsg, globalKeyMap keybindings.GlobalKeyMap) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case globalKeyMap.Quit.Keys()[0]:
+		if m.cancel != nil {
+			m.cancel()
+		}
+		return m, tea.Quit
+	case "up", "k", "shift+tab":
+		if m.serverCursor > 0 {
+			m.serverCursor--
+		}
+		return m, nil
+	case "down", "j", "tab":
+		if m.serverCursor < len(m.servers)-1 {
+			m.serverCursor++
+		}
+		return m, nil
+	case "r":
+		return m, m.probeServersCmd()
+	case "enter":
+		return m, m.switchServer(m.serverCursor)
+	case "esc", globalKeyMap.Servers.Keys()[0]:
+		m.closeServerPopup()
+		return m, nil
+	}
+	return m, nil
+}
+
+// switchServer replaces the client with one for the chosen server, keeping
+// the chat settings, and reloads the panels
+func (m *Model) switchServer(index int) tea.Cmd {
+	if index < 0 || index >= len(m.servers) {
+		return nil
+	}
+	entry := m.servers[index]
+	if entry.profile.Err != nil {
+		return func() tea.Msg {
+			return logMsg(fmt.Sprintf("Cannot use server %s: %v", entry.profile.Name, entry.profile.Err))
+		}
+	}
+	if index == m.activeServer() {
+		m.closeServerPopup()
+		return nil
+	}
+	if m.streaming {
+		return func() tea.Msg { return logMsg("Cancel the running response before switching servers") }
+	}
+
+	previous := m.client
+	config := previous.GetConfig().WithServer(entry.profile.Config)
+	next, err := client.NewClientWithConfig(m.ctx, config, m.logChan)
+	if err != nil {
+		return func() tea.Msg {
+			return logMsg(fmt.Sprintf("Failed to switch to server %s: %v", entry.profile.Name, err))
+		}
+	}
+
+	if err := next.SetReasoningEffort(previous.ReasoningEffort()); err != nil {
+		next.GetLogger().Warn("Keeping the default reasoning effort: %v", err)
+	}
+	if err := next.SetSamplingParams(previous.SamplingParams()); err != nil {
+		next.GetLogger().Warn("Keeping the default sampling parameters: %v", err)
+	}
+	next.SetRetrieval(previous.RetrievalEnabled())
+	if schema := previous.Schema(); schema != nil {
+		next.SetSchema(schema)
+	}
+	if config.Tools {
+		if err := next.RegisterBuiltinTools(); err != nil {
+			next.GetLogger().Warn("Failed to register tools: %v", err)
+		}
+	}
+	if m.systemPrompt != "" {
+		if err := next.SetSystemMessage(m.systemPrompt); err != nil {
+			next.GetLogger().Warn("Dropping system prompt: %v", err)
+		}
+	}
+
+	previous.CancelRequest()
+	previous.Cleanup()
+	m.client = next
+
+	// Models and conversations belong to the previous server
+	m.status = false
+	m.chatMessages = []rendering.ChatMessage{}
+	m.contextTokens = m.client.ConversationTokens()
+	m.chatViewport.SetContent("")
+	m.chatViewport.GotoTop()
+	m.loadedModels = nil
+	m.downloadedModels = nil
+	m.loadedList.ResetFilter()
+	m.downloadedList.ResetFilter()
+	m.loadedList.SetItems([]list.Item{})
+	m.downloadedList.SetItems([]list.Item{})
+	m.selectedModel = ""
+	m.explicitlySelectedModel = ""
+	m.firstLoad = true
+	m.lastLoadedModelIDs = []string{}
+	m.chatInput.Placeholder = NoModelsPlaceholder
+	m.closeServerPopup()
+
+	m.client.GetLogger().Info("Switched to server %s (%s)", entry.profile.Name, config.GetFullURL())
+	return tea.Batch(
+		m.updateStatusCmd(),
+		m.updateModelsCmd(),
+	)
+}
+
+// serverReachability describes the probe result of a server
+func serverReachability(entry serverEntry) string {
+	switch {
+	case entry.profile.Err != nil:
+		return lipgloss.NewStyle().Foreground(styles.ColorOrange).Render("✗ invalid profile")
+	case entry.probe == nil:
+		return lipgloss.NewStyle().Foreground(styles.ColorGray).Render("… checking")
+	case entry.probe.Reachable:
+		return lipgloss.NewStyle().Foreground(styles.ColorGreen).Render(fmt.Sprintf("● %dms", entry.probe.Latency.Milliseconds()))
+	default:
+		return lipgloss.NewStyle().Foreground(styles.ColorOrange).Render("✗ unreachable")
+	}
+}
+
+// renderServerPopup renders the server switcher
+func (m Model) renderServerPopup() string {
+	active := m.activeServer()
+	width := serverPopupWidth - 8
+
+	lines := make([]string, 0, len(m.servers)+1)
+	for i, entry := range m.servers {
+		cursor := "  "
+		if i == m.serverCursor {
+			cursor = "▸ "
+		}
+		name := entry.profile.Name
+		if i == active {
+			name += " (active)"
+		}
+		url := entry.profile.Config.GetFullURL()
+		if entry.profile.Err != nil {
+			url = ""
+		}
+
+		line := fmt.Sprintf("%s%-22s %-26s %s", cursor, truncate(name, 22), truncate(url, 26), serverReachability(entry))
+		style := lipgloss.NewStyle()
+		if i == m.serverCursor {
+			style = style.Bold(true)
+		}
+		lines = append(lines, style.Render(line))
+	}
+
+	// Explain why the selected server cannot be used
+	if m.serverCursor < len(m.servers) {
+		entry := m.servers[m.serverCursor]
+		detail := ""
+		if entry.profile.Err != nil {
+			detail = entry.profile.Err.Error()
+		} else if entry.probe != nil && entry.probe.Err != nil {
+			detail = entry.probe.Err.Error()
+		}
+		if detail != "" {
+			lines = append(lines, "", lipgloss.NewStyle().Foreground(styles.ColorGray).Width(width).Render(detail))
+		}
+	}
+
+	instructions := "↑↓ move, Enter switch, R check again, Esc cancel"
+	instructionsStyle := lipgloss.NewStyle().
+		Foreground(styles.ColorGray).
+		Align(lipgloss.Center).
+		Width(width)
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		"",
+		strings.Join(lines, "\n"),
+		"",
+		instructionsStyle.Render(instructions),
+		"",
+	)
+
+	embeddedText := map[layout.BorderPosition]string{
+		layout.TopLeftBorder: "⇄ Servers",
+	}
+
+	popup := layout.Borderize(content, true, serverPopupWidth, lipgloss.Height(content)+2, embeddedText)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, popup)
+}
+
+// truncate shortens text to width runes, marking the cut with an ellipsis
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Rugz007/lazylms/pkg/client"
+)
+
+// handleSlashCommand runs chat input starting with a slash. It reports
+// false when the input is not a known command and should be sent as a
+// message instead.
+func (m *Model) handleSlashCommand(input string) (tea.Cmd, bool) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(input), " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case "/image":
+		return m.attachImage(arg), true
+	case "/index":
+		return m.indexDirectory(arg), true
+	case "/rag":
+		return m.toggleRetrieval(arg), true
+	case "/schema":
+		return m.setSchema(arg), true
+	}
+	return nil, false
+}
+
+// attachImage queues an image for the next message, or clears the queue
+// when called without a path
+func (m *Model) attachImage(path string) tea.Cmd {
+	if path == "" || path == "clear" {
+		m.pendingImages = nil
+		return func() tea.Msg { return logMsg("Cleared image attachments") }
+	}
+
+	model, ok := m.selectedLoadedModel()
+	if !ok {
+		return func() tea.Msg { return logMsg("No model selected. Select a vision model before attaching images.") }
+	}
+	if !model.Vision {
+		return func() tea.Msg {
+			return logMsg(fmt.Sprintf("Model %s does not accept images", model.Identifier))
+		}
+	}
+
+	image, err := client.LoadImageAttachment(expandHome(path))
+	if err != nil {
+		return func() tea.Msg { return logMsg(fmt.Sprintf("Failed to attach image: %v", err)) }
+	}
+
+	m.pendingImages = append(m.pendingImages, image)
+	count := len(m.pendingImages)
+	return func() tea.Msg {
+		return logMsg(fmt.Sprintf("Attached %s (%d image(s) will be sent with the next message)", image.Name, count))
+	}
+}
+
+// indexDirectory embeds the text files of a directory in the background
+func (m *Model) indexDirectory(dir string) tea.Cmd {
+	if dir == "" {
+		return func() tea.Msg { return logMsg("Usage: /index <directory>") }
+	}
+
+	lmsClient := m.client
+	ctx := m.ctx
+	dir = expandHome(dir)
+	return func() tea.Msg {
+		count, err := lmsClient.IndexDirectory(ctx, dir)
+		if err != nil {
+			return logMsg(fmt.Sprintf("Indexing failed: %v", err))
+		}
+		return logMsg(fmt.Sprintf("Indexed %d chunks from %s", count, dir))
+	}
+}
+
+// toggleRetrieval turns retrieval on or off, flipping it without an argument
+func (m *Model) toggleRetrieval(arg string) tea.Cmd {
+	switch arg {
+	case "on":
+		m.client.SetRetrieval(true)
+	case "off":
+		m.client.SetRetrieval(false)
+	case "":
+		m.client.SetRetrieval(!m.client.RetrievalEnabled())
+	default:
+		return func() tea.Msg { return logMsg("Usage: /rag [on|off]") }
+	}
+	return nil
+}
+
+// setSchema requests structured output following a schema file, or turns
+// structured output off
+func (m *Model) setSchema(path string) tea.Cmd {
+	switch path {
+	case "":
+		return func() tea.Msg { return logMsg("Usage: /schema <file.json> or /schema off") }
+	case "off":
+		m.client.SetSchema(nil)
+		return nil
+	}
+
+	schema, err := client.LoadJSONSchema(expandHome(path))
+	if err != nil {
+		return func() tea.Msg { return logMsg(fmt.Sprintf("Failed to load schema: %v", err)) }
+	}
+	m.client.SetSchema(schema)
+	return nil
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
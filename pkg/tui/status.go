@@ -21,11 +21,18 @@ func (m Model) renderStatusView(_, leftColumnWidth int) string {
 		content = lipgloss.NewStyle().Background(styles.ColorYellow).Padding(0, 1).Foreground(styles.ColorForegroundInverted).Render(statusText)
 	}
 	title := lipgloss.NewStyle().Italic(true).Render("👾  lazylms")
-	content = lipgloss.NewStyle().Padding(1).Render(title + "\nAPI Server: " + content)
+	url := lipgloss.NewStyle().Foreground(styles.ColorGray).Render(truncate(m.client.GetConfig().GetFullURL(), leftColumnWidth-6))
+	content = lipgloss.NewStyle().Padding(1, 1, 0).Render(title + "\nAPI Server: " + content + "\n" + url)
 
 	embeddedText := map[layout.BorderPosition]string{
 		layout.TopLeftBorder: "[1] ◉ Status",
 	}
+	if m.client.GetConfig().TLS.InsecureSkipVerify {
+		embeddedText[layout.TopRightBorder] = lipgloss.NewStyle().Foreground(styles.ColorOrange).Render("⚠ insecure TLS")
+	}
+	if index := m.activeServer(); index >= 0 {
+		embeddedText[layout.BottomLeftBorder] = m.servers[index].profile.Name
+	}
 
 	return layout.Borderize(content, active, leftColumnWidth-2, 6, embeddedText)
 }
@@ -3,6 +3,8 @@ package tui
 import (
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/Rugz007/lazylms/pkg/client"
 )
 
@@ -24,13 +26,52 @@ type modelsMsg struct {
 	loaded     []client.LMSLoadedListItem
 }
 
-type estimatesMsg []client.LMSDownloadedListItem
+// estimatesMsg carries one load estimate, the others are read from results
+// as they arrive
+type estimatesMsg struct {
+	result  client.EstimateResult
+	results <-chan client.EstimateResult
+}
+
+// estimatesDoneMsg reports that every estimate of a run has arrived
+type estimatesDoneMsg struct{}
+
+// downloadProgressMsg carries the latest progress of a download
+type downloadProgressMsg struct {
+	job      *client.DownloadJob
+	progress client.DownloadProgress
+}
+
+// downloadResolvedMsg carries the model a search term of the download
+// popup resolved to
+type downloadResolvedMsg struct {
+	term  string
+	model string
+	err   error
+}
+
+// downloadDoneMsg reports that a download ended, successfully or not
+type downloadDoneMsg struct {
+	job *client.DownloadJob
+}
+
+// listMsg is a message of the model list shown by view
+type listMsg struct {
+	view string
+	msg  tea.Msg
+}
 
 type logListenerMsg struct{}
 
 type streamChunkMsg struct {
 	content     string
-	contentType string // "output" or "reasoning"
+	contentType string // "output", "reasoning" or "tool"
+}
+
+// serverProbeMsg carries the reachability of a server of the server switcher
+type serverProbeMsg struct {
+	index int
+	probe client.ServerProbe
 }
 
 type streamCompleteMsg struct{}
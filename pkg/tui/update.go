@@ -1,19 +1,17 @@
 package tui
 
 import (
+	"cmp"
 	"fmt"
 	"regexp"
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Rugz007/lazylms/pkg/client"
 	"github.com/Rugz007/lazylms/pkg/tui/keybindings"
 	"github.com/Rugz007/lazylms/pkg/tui/rendering"
-	"github.com/Rugz007/lazylms/pkg/tui/styles"
 )
 
 // extractModelIDs extracts identifiers from loaded models
@@ -88,9 +86,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.firstLoad = false
 			m.lastEstimateTime = time.Now()
 			m.lastLoadedModelIDs = currentLoadedIDs
+			estimates := m.runEstimates()
 			return m, tea.Batch(
 				estimateTickCmd(),
-				m.updateEstimatesCmd(),
+				estimates,
 			)
 		}
 
@@ -103,22 +102,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case statusMsg:
 		m.status = bool(msg)
 
+	case serverProbeMsg:
+		if msg.index < len(m.servers) {
+			m.servers[msg.index].probe = &msg.probe
+		}
+
 	case modelsMsg:
 		previousLoadedModels := m.loadedModels
 		m.downloadedModels = msg.downloaded
 		m.loadedModels = msg.loaded
 
-		loadedItems := make([]list.Item, len(msg.loaded))
-		for i, model := range msg.loaded {
-			loadedItems[i] = loadedModelItem{model: model}
+		// Remember when lms last saw the models used, for the recent sort
+		var cmds []tea.Cmd
+		if m.preferences != nil {
+			changed := false
+			for _, model := range msg.loaded {
+				if model.LastUsedTime > 0 && m.preferences.SetLastUsed(model.ModelKey, model.LastUsedTime) {
+					changed = true
+				}
+			}
+			if changed {
+				cmds = append(cmds, m.savePreferencesCmd())
+			}
 		}
-		m.loadedList.SetItems(loadedItems)
 
-		downloadedItems := make([]list.Item, len(msg.downloaded))
-		for i, model := range msg.downloaded {
-			downloadedItems[i] = downloadedModelItem{model: model}
-		}
-		m.downloadedList.SetItems(downloadedItems)
+		cmds = append(cmds, m.refreshLoadedList(), m.refreshDownloadedList())
 
 		// Auto-select most recently loaded model
 		if len(msg.loaded) > 0 && len(previousLoadedModels) < len(msg.loaded) {
@@ -128,14 +136,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				previousIdentifiers[model.Identifier] = true
 			}
 
-			// Find the first new model and auto-select it
+			// Auto-select the configured default model if it is new, the
+			// first new model otherwise
+			defaultModel := m.client.GetConfig().DefaultModel
+			newModel := ""
 			for _, model := range msg.loaded {
-				if !previousIdentifiers[model.Identifier] {
-					m.explicitlySelectedModel = model.Identifier
-					m.selectedModel = model.Identifier
-					break
+				if previousIdentifiers[model.Identifier] {
+					continue
+				}
+				if newModel == "" || (defaultModel != "" && (model.Identifier == defaultModel || model.ModelKey == defaultModel)) {
+					newModel = model.Identifier
 				}
 			}
+			if newModel != "" {
+				m.explicitlySelectedModel = newModel
+				m.selectedModel = newModel
+				m.applyModelPreferences()
+			}
 		}
 
 		// Check if explicitly selected model is still available
@@ -165,7 +182,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.lastLoadedModelIDs = currentLoadedIDs
 
-			return m, m.updateEstimatesCmd()
+			cmds = append(cmds, m.runEstimates())
+			return m, tea.Batch(cmds...)
 		}
 
 		if len(msg.loaded) == 0 {
@@ -175,28 +193,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.chatInput.Placeholder = SelectModelPlaceholder
 		}
+		return m, tea.Batch(cmds...)
 
 	case estimatesMsg:
-		estimateMap := make(map[string]bool)
-		for _, model := range msg {
-			estimateMap[model.ModelKey] = model.CanLoad
+		client.ApplyEstimate(m.downloadedModels, msg.result)
+		if msg.result.Err != nil {
+			m.estimateFailures++
+			m.estimateErr = cmp.Or(m.estimateErr, msg.result.Err)
 		}
 
-		for i, model := range m.downloadedModels {
-			if canLoad, exists := estimateMap[model.ModelKey]; exists {
-				m.downloadedModels[i].CanLoad = canLoad
-			}
+		// Update list items with new CanLoad status
+		return m, tea.Batch(m.refreshDownloadedList(), waitForEstimate(msg.results))
+
+	case estimatesDoneMsg:
+		m.estimating = false
+		var cmds []tea.Cmd
+		if err := client.EstimateFailure(m.estimateFailures, m.estimateErr); err != nil {
+			cmds = append(cmds, func() tea.Msg { return logMsg(err.Error()) })
+		}
+		if m.estimatesQueued {
+			m.estimatesQueued = false
+			cmds = append(cmds, m.runEstimates())
 		}
+		return m, tea.Batch(cmds...)
 
-		// Update list items with new CanLoad status
-		downloadedItems := make([]list.Item, len(m.downloadedModels))
-		for i, model := range m.downloadedModels {
-			downloadedItems[i] = downloadedModelItem{model: model}
+	case downloadProgressMsg:
+		if msg.job == m.download {
+			m.downloadProgress = msg.progress
+		}
+		return m, waitForDownload(msg.job)
+
+	case downloadResolvedMsg:
+		return m, m.handleDownloadResolved(msg)
+
+	case downloadDoneMsg:
+		// The client logs how the download ended
+		if msg.job != m.download {
+			return m, nil
+		}
+		m.download = nil
+		if msg.job.Err() != nil {
+			return m, nil
 		}
-		m.downloadedList.SetItems(downloadedItems)
+		// Estimate again on the next tick, only the new model misses the cache
+		m.firstLoad = true
+		return m, m.updateModelsCmd()
 
 	case logMsg:
-		// Add log message and keep only last MaxLogLines messages
+		// Add log message and keep only the last ui.max_log_lines messages
 		logLines := strings.Split(m.logsViewport.View(), "\n")
 		if len(logLines) == 1 && logLines[0] == "" {
 			logLines = []string{}
@@ -204,8 +248,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Wrap the new log message to viewport width
 		wrappedMsg := rendering.WrapText(string(msg), m.logsViewport.Width-2)
 		logLines = append(logLines, wrappedMsg)
-		if len(logLines) > MaxLogLines {
-			logLines = logLines[len(logLines)-MaxLogLines:]
+		if maxLines := m.client.GetConfig().UI.MaxLogLines; len(logLines) > maxLines {
+			logLines = logLines[len(logLines)-maxLines:]
 		}
 		m.logsViewport.SetContent(strings.Join(logLines, "\n"))
 		m.logsViewport.GotoBottom()
@@ -217,6 +261,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.streaming = false
 
 			// Restore the original status of the selected model
+			var listCmd tea.Cmd
 			if m.originalStreamingStatus != "" {
 				for i, model := range m.loadedModels {
 					if model.Identifier == m.selectedModel {
@@ -227,27 +272,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				// Update the loaded list items to reflect the change
-				loadedItems := make([]list.Item, len(m.loadedModels))
-				for i, model := range m.loadedModels {
-					loadedItems[i] = loadedModelItem{model: model}
-				}
-				m.loadedList.SetItems(loadedItems)
+				listCmd = m.refreshLoadedList()
 			}
 
 			aiMsg := rendering.ChatMessage{
 				Type:     rendering.MessageTypeAI,
 				Author:   m.selectedModel,
+				Preset:   m.activePreset,
+				Metrics:  messageMetrics(m.client.LastMetrics()),
+				Sources:  m.responseSources,
 				Segments: m.currentResponse.Segments,
 			}
+			if validation := m.client.LastValidation(); validation != nil {
+				aiMsg.Schema = schemaStatus(validation)
+				aiMsg.Segments = structuredSegments(aiMsg.Segments)
+			}
+			m.responseSources = nil
 			m.chatMessages = append(m.chatMessages, aiMsg)
 
 			// Add response to client conversation for context
 			// Build content for assistant message from segments
 			var responseContent strings.Builder
 			for _, seg := range m.currentResponse.Segments {
+				if seg.Type == rendering.ContentTypeTool {
+					continue
+				}
 				responseContent.WriteString(seg.Text)
 			}
 			m.client.AddAssistantMessage(responseContent.String())
+			m.contextTokens = m.client.ConversationTokens()
 
 			m.currentResponse.Reset()
 			m.chatInput.Placeholder = fmt.Sprintf(ChatWithModelPlaceholder, m.selectedModel)
@@ -259,11 +312,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			content := strings.Join(renderedMessages, "\n")
 			m.chatViewport.SetContent(content)
 			m.chatViewport.GotoBottom()
-			return m, nil
+			return m, listCmd
 		} else if strings.HasPrefix(chunk, "ERROR:") {
 			m.streaming = false
 
 			// Restore the original status of the selected model
+			var listCmd tea.Cmd
 			if m.originalStreamingStatus != "" {
 				for i, model := range m.loadedModels {
 					if model.Identifier == m.selectedModel {
@@ -273,17 +327,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 
-				loadedItems := make([]list.Item, len(m.loadedModels))
-				for i, model := range m.loadedModels {
-					loadedItems[i] = loadedModelItem{model: model}
-				}
-				m.loadedList.SetItems(loadedItems)
+				listCmd = m.refreshLoadedList()
 			}
 
+			m.contextTokens = m.client.ConversationTokens()
 			errorMsg := strings.TrimPrefix(chunk, "ERROR:")
 			m.chatInput.Placeholder = fmt.Sprintf(ChatWithModelPlaceholder, m.selectedModel)
-			return m, tea.Cmd(func() tea.Msg { return logMsg(fmt.Sprintf("Streaming error: %s", errorMsg)) })
+			return m, tea.Batch(listCmd, func() tea.Msg { return logMsg(fmt.Sprintf("Streaming error: %s", errorMsg)) })
 		} else {
+			if msg.contentType == client.ContentTypeSummary {
+				m.insertSummary(chunk)
+				return m, m.streamSubscription()
+			}
+			if msg.contentType == client.ContentTypeSources {
+				m.responseSources = strings.Split(strings.TrimSpace(chunk), "\n")
+				return m, m.streamSubscription()
+			}
+
+			if msg.contentType == client.ContentTypeReasoning && m.client.GetConfig().UI.HideReasoning {
+				return m, m.streamSubscription()
+			}
+
 			// Regular chunk - add segment
 			m.currentResponse.AddSegment(chunk, msg.contentType)
 			// Update chat viewport with partial response - render all messages with markdown
@@ -294,10 +358,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			content := strings.Join(renderedMessages, "\n")
 			if m.streaming {
 				// Render streaming message with segments
-				styledPrefix := lipgloss.NewStyle().
-					Foreground(styles.ColorPurple).
-					Bold(true).
-					Render(m.selectedModel + ":")
+				styledPrefix := rendering.RenderAIPrefix(m.selectedModel, m.activePreset)
 				rendered := rendering.RenderMixedContent(m.currentResponse.Segments, m.chatViewport.Width, m.logChan)
 				content += "\n" + styledPrefix + rendered
 			}
@@ -314,6 +375,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		default:
 			return m, m.logListenerCmd()
 		}
+	case listMsg:
+		return m, m.updateList(msg.view, msg.msg)
+
 	case nextViewMsg:
 		m.currentView = string(msg)
 		return m, nil
@@ -323,6 +387,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleKeyMsg(msg tea.KeyMsg, globalKeyMap keybindings.GlobalKeyMap, viewKeyMap keybindings.ViewKeyMap, chatKeyMap keybindings.ChatKeyMap, listKeyMap keybindings.ListKeyMap) (tea.Model, tea.Cmd) {
+	if m.showSamplingPopup {
+		return m.handleSamplingPopupKeys(msg, globalKeyMap)
+	}
+
+	if m.showServerPopup {
+		return m.handleServerPopupKeys(msg, globalKeyMap)
+	}
+
+	if m.showLoadPopup {
+		return m.handleLoadPopupKeys(msg, globalKeyMap)
+	}
+
+	if m.showDownloadPopup {
+		return m.handleDownloadPopupKeys(msg, globalKeyMap)
+	}
+
 	if m.currentView == "chat" && m.chatInput.Focused() {
 		return m.handleChatInputKeys(msg, globalKeyMap, chatKeyMap)
 	}
@@ -332,6 +412,17 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg, globalKeyMap keybindings.GlobalKeyMa
 		return m.handleSystemInputKeys(msg, globalKeyMap)
 	}
 
+	// While a list search is typed every key belongs to the list
+	if m.searchingList(msg) {
+		if msg.String() == globalKeyMap.Quit.Keys()[0] {
+			if m.cancel != nil {
+				m.cancel()
+			}
+			return m, tea.Quit
+		}
+		return m, m.updateList(m.currentView, msg)
+	}
+
 	switch msg.String() {
 	case globalKeyMap.Quit.Keys()[0]:
 		if m.cancel != nil {
@@ -373,9 +464,17 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg, globalKeyMap keybindings.GlobalKeyMa
 	case globalKeyMap.Help.Keys()[0], globalKeyMap.Help.Keys()[1]:
 		m.showHelp = !m.showHelp
 		return m, nil
+	case globalKeyMap.Reasoning.Keys()[0]:
+		return m, m.cycleReasoningEffort()
+	case globalKeyMap.Sampling.Keys()[0]:
+		m.openSamplingPopup()
+		return m, nil
+	case globalKeyMap.Servers.Keys()[0]:
+		return m, m.openServerPopup()
 	case globalKeyMap.ClearChat.Keys()[0]:
 		m.chatMessages = []rendering.ChatMessage{}
 		m.client.ClearConversation()
+		m.contextTokens = 0
 		m.chatViewport.SetContent("")
 		m.chatViewport.GotoTop()
 		return m, tea.Cmd(func() tea.Msg { return logMsg("Chat cleared") })
@@ -389,10 +488,12 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg, globalKeyMap keybindings.GlobalKeyMa
 		return m, nil
 
 	case listKeyMap.Unload.Keys()[0]:
-		if m.currentView == "loaded" && len(m.loadedModels) > 0 {
-			selectedIndex := m.loadedList.Index()
-			if selectedIndex < len(m.loadedModels) {
-				modelID := m.loadedModels[selectedIndex].Identifier
+		if m.currentView == "loaded" {
+			if cmd := m.requireModelManagement(); cmd != nil {
+				return m, cmd
+			}
+			if item, ok := m.loadedList.SelectedItem().(loadedModelItem); ok {
+				modelID := item.model.Identifier
 				if modelID == m.explicitlySelectedModel {
 					m.explicitlySelectedModel = ""
 					m.selectedModel = ""
@@ -402,18 +503,40 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg, globalKeyMap keybindings.GlobalKeyMa
 		}
 	case listKeyMap.UnloadAll.Keys()[0]:
 		if m.currentView == "loaded" {
+			if cmd := m.requireModelManagement(); cmd != nil {
+				return m, cmd
+			}
 			m.explicitlySelectedModel = ""
 			m.selectedModel = ""
 			return m, m.unloadAllModelsCmd()
 		}
+	case listKeyMap.Download.Keys()[0]:
+		if m.currentView == "downloaded" {
+			if cmd := m.requireModelManagement(); cmd != nil {
+				return m, cmd
+			}
+			return m, m.openDownloadPopup()
+		}
+	case listKeyMap.CancelDownload.Keys()[0]:
+		if m.currentView == "downloaded" {
+			m.cancelDownload()
+			return m, nil
+		}
+	case listKeyMap.Sort.Keys()[0]:
+		return m, m.cycleListSort()
+	case listKeyMap.Group.Keys()[0]:
+		return m, m.cycleListGrouping()
 	case "enter":
+		if cmd, ok := m.toggleHighlightedGroup(); ok {
+			return m, cmd
+		}
 		if m.currentView == "downloaded" {
 			return m, m.handleDownloadedModelSelection()
-		} else if m.currentView == "loaded" && len(m.loadedModels) > 0 {
-			selectedIndex := m.loadedList.Index()
-			if selectedIndex < len(m.loadedModels) {
-				m.explicitlySelectedModel = m.loadedModels[selectedIndex].Identifier
+		} else if m.currentView == "loaded" {
+			if item, ok := m.loadedList.SelectedItem().(loadedModelItem); ok {
+				m.explicitlySelectedModel = item.model.Identifier
 				m.selectedModel = m.explicitlySelectedModel
+				m.applyModelPreferences()
 				return m, tea.Cmd(func() tea.Msg { return logMsg(fmt.Sprintf("Selected model: %s", m.selectedModel)) })
 			}
 		} else if m.currentView == "system" {
@@ -422,20 +545,15 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg, globalKeyMap keybindings.GlobalKeyMa
 			if systemPrompt != "" {
 				m.systemPrompt = systemPrompt
 				m.client.SetSystemMessage(systemPrompt)
+				m.contextTokens = m.client.ConversationTokens()
 				m.systemInput.SetValue("")
 				return m, nil
 			}
 		}
 	default:
 		switch m.currentView {
-		case "loaded":
-			var cmd tea.Cmd
-			m.loadedList, cmd = m.loadedList.Update(msg)
-			return m, cmd
-		case "downloaded":
-			var cmd tea.Cmd
-			m.downloadedList, cmd = m.downloadedList.Update(msg)
-			return m, cmd
+		case "loaded", "downloaded":
+			return m, m.updateList(m.currentView, msg)
 		case "logs":
 			return m.handleLogsKeys(msg, chatKeyMap)
 		case "chat":
@@ -472,9 +590,17 @@ func (m Model) handleChatInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Glob
 		return m, nil
 	case globalKeyMap.NextView.Keys()[0]:
 		return m, m.nextViewCmd()
+	case globalKeyMap.Reasoning.Keys()[0]:
+		return m, m.cycleReasoningEffort()
+	case globalKeyMap.Sampling.Keys()[0]:
+		m.openSamplingPopup()
+		return m, nil
+	case globalKeyMap.Servers.Keys()[0]:
+		return m, m.openServerPopup()
 	case globalKeyMap.ClearChat.Keys()[0]:
 		m.chatMessages = []rendering.ChatMessage{}
 		m.client.ClearConversation()
+		m.contextTokens = 0
 		m.chatViewport.SetContent("")
 		m.chatViewport.GotoTop()
 		return m, tea.Cmd(func() tea.Msg { return logMsg("Chat cleared") })
@@ -489,10 +615,16 @@ func (m Model) handleChatInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Glob
 			return m, nil
 		}
 		message := m.chatInput.Value()
+		if strings.HasPrefix(message, "/") {
+			if cmd, ok := m.handleSlashCommand(message); ok {
+				m.chatInput.SetValue("")
+				return m, cmd
+			}
+		}
 		if message != "" {
 			if m.selectedModel == "" {
 				return m, tea.Cmd(func() tea.Msg {
-					return logMsg("No model selected. Please select a model first using 's' key in loaded models view.")
+					return logMsg("No model selected. Please select a model first with Enter in the loaded models view.")
 				})
 			}
 
@@ -501,11 +633,16 @@ func (m Model) handleChatInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Glob
 			if m.hasWelcomeMessage {
 				m.hasWelcomeMessage = false
 			}
+			images := m.pendingImages
+			m.pendingImages = nil
 			userMsg := rendering.ChatMessage{
 				Type:    rendering.MessageTypeUser,
 				Author:  "You",
 				Content: message,
 			}
+			for _, image := range images {
+				userMsg.Images = append(userMsg.Images, image.Name)
+			}
 			m.chatMessages = append(m.chatMessages, userMsg)
 			// Update viewport with new message - render all messages with markdown
 			var renderedMessages []string
@@ -519,10 +656,13 @@ func (m Model) handleChatInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Glob
 			m.streaming = true
 			m.chatInput.Placeholder = GeneratingPlaceholder
 			m.currentResponse.Reset()
+			m.responseSources = nil
 
 			// Update the selected model's status to "generating"
+			modelKey := ""
 			for i, model := range m.loadedModels {
 				if model.Identifier == m.selectedModel {
+					modelKey = model.ModelKey
 					m.originalStreamingStatus = model.Status
 					m.loadedModels[i].Status = "generating"
 					break
@@ -530,14 +670,10 @@ func (m Model) handleChatInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Glob
 			}
 
 			// Update the loaded list items to reflect the change
-			loadedItems := make([]list.Item, len(m.loadedModels))
-			for i, model := range m.loadedModels {
-				loadedItems[i] = loadedModelItem{model: model}
-			}
-			m.loadedList.SetItems(loadedItems)
+			listCmd := m.refreshLoadedList()
 			// Send the message using streaming
 			go func() {
-				err := m.client.SendMessageStreamWithModel(m.ctx, message, m.selectedModel, func(chunk string, contentType string) {
+				err := m.client.SendMessageStreamWithAttachments(m.ctx, message, m.selectedModel, images, func(chunk string, contentType string) {
 					select {
 					case m.streamChan <- streamChunkMsg{content: chunk, contentType: contentType}:
 					default:
@@ -558,7 +694,7 @@ func (m Model) handleChatInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Glob
 					}
 				}
 			}()
-			return m, m.streamSubscription()
+			return m, tea.Batch(listCmd, m.touchModel(modelKey), m.streamSubscription())
 		}
 		return m, nil
 	case chatKeyMap.Cancel.Keys()[0]:
@@ -584,6 +720,9 @@ func (m Model) handleChatInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Glob
 				// Build content from segments
 				var responseContent strings.Builder
 				for _, seg := range m.currentResponse.Segments {
+					if seg.Type == rendering.ContentTypeTool {
+						continue
+					}
 					responseContent.WriteString(seg.Text)
 				}
 				// Append cancelled marker as output segment
@@ -595,12 +734,15 @@ func (m Model) handleChatInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Glob
 				aiMsg := rendering.ChatMessage{
 					Type:     rendering.MessageTypeAI,
 					Author:   m.selectedModel,
+					Preset:   m.activePreset,
+					Sources:  m.responseSources,
 					Segments: cancelledSegments,
 				}
 				m.chatMessages = append(m.chatMessages, aiMsg)
 
 				// Add partial response to client conversation for context
 				m.client.AddAssistantMessage(responseContent.String())
+				m.contextTokens = m.client.ConversationTokens()
 
 				m.currentResponse.Reset()
 
@@ -615,6 +757,7 @@ func (m Model) handleChatInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Glob
 			}
 
 			// Restore the original status of the selected model
+			var listCmd tea.Cmd
 			if m.originalStreamingStatus != "" {
 				for i, model := range m.loadedModels {
 					if model.Identifier == m.selectedModel {
@@ -625,17 +768,16 @@ func (m Model) handleChatInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Glob
 				}
 
 				// Update the loaded list items to reflect the change
-				loadedItems := make([]list.Item, len(m.loadedModels))
-				for i, model := range m.loadedModels {
-					loadedItems[i] = loadedModelItem{model: model}
-				}
-				m.loadedList.SetItems(loadedItems)
+				listCmd = m.refreshLoadedList()
 			}
 
 			m.chatInput.Placeholder = fmt.Sprintf(ChatWithModelPlaceholder, m.selectedModel)
-			return m, tea.Cmd(func() tea.Msg { return logMsg("Request cancelled by user") })
+			return m, tea.Batch(listCmd, func() tea.Msg { return logMsg("Request cancelled by user") })
 		}
 		return m, nil
+	case chatKeyMap.Summaries.Keys()[0]:
+		m.toggleSummaries()
+		return m, nil
 	case chatKeyMap.ExitChat.Keys()[0]:
 		m.currentView = "status"
 		m.chatInput.Blur()
@@ -667,6 +809,7 @@ func (m Model) handleSystemInputKeys(msg tea.KeyMsg, globalKeyMap keybindings.Gl
 		if systemPrompt != "" {
 			m.systemPrompt = systemPrompt
 			m.client.SetSystemMessage(systemPrompt)
+			m.contextTokens = m.client.ConversationTokens()
 			m.systemInput.SetValue("")
 			m.showSystemPopup = false
 			m.systemInput.Blur()
@@ -739,6 +882,9 @@ func (m Model) handleChatViewportKeys(msg tea.KeyMsg, chatKeyMap keybindings.Cha
 	case chatKeyMap.End.Keys()[0]:
 		m.chatViewport.GotoBottom()
 		return m, nil
+	case chatKeyMap.Summaries.Keys()[0]:
+		m.toggleSummaries()
+		return m, nil
 	case "enter":
 		// This case is now handled by the main input handling above
 		return m, nil